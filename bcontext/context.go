@@ -13,6 +13,8 @@ type defaultContext struct {
 	timer  context.Context
 	cancel context.CancelFunc
 
+	warnings []Warning
+
 	sync.RWMutex
 }
 
@@ -29,7 +31,9 @@ func NewWithCtx(ctx context.Context) Context {
 		kv:   make(map[string]any),
 	}
 	if deadline, exist := ctx.Deadline(); exist {
-		out.timer, out.cancel = context.WithDeadline(out, deadline)
+		// derive from the original context rather than out,
+		// so that cancelling the timer never calls back into out under its own lock
+		out.timer, out.cancel = context.WithDeadline(ctx, deadline)
 	}
 	return out
 }
@@ -37,12 +41,12 @@ func NewWithCtx(ctx context.Context) Context {
 // WithTimeout override context timeout/cancel
 func (ctx *defaultContext) WithTimeout(timeout time.Duration) {
 	ctx.Lock()
-	defer ctx.Unlock()
-	_, origCancel := ctx.timer, ctx.cancel
+	origCancel := ctx.cancel
 	// reset timeout
 	ctx.timer, ctx.cancel = context.WithTimeout(context.Background(), timeout)
+	ctx.Unlock()
 	if origCancel != nil {
-		// release original timer
+		// release original timer outside the lock
 		origCancel()
 	}
 	return
@@ -51,12 +55,12 @@ func (ctx *defaultContext) WithTimeout(timeout time.Duration) {
 // WithCancel override context timeout/cancel
 func (ctx *defaultContext) WithCancel() {
 	ctx.Lock()
-	defer ctx.Unlock()
-	_, origCancel := ctx.timer, ctx.cancel
+	origCancel := ctx.cancel
 	// reset timeout
 	ctx.timer, ctx.cancel = context.WithCancel(context.Background())
+	ctx.Unlock()
 	if origCancel != nil {
-		// release original timer
+		// release original timer outside the lock
 		origCancel()
 	}
 	return
@@ -64,8 +68,11 @@ func (ctx *defaultContext) WithCancel() {
 
 // Cancel trigger context timeout early
 func (ctx *defaultContext) Cancel() {
-	if ctx.cancel != nil {
-		ctx.cancel()
+	ctx.RLock()
+	cancel := ctx.cancel
+	ctx.RUnlock()
+	if cancel != nil {
+		cancel()
 	}
 }
 
@@ -78,9 +85,16 @@ func (ctx *defaultContext) GetOrigCtx() (context.Context, bool) {
 	return nil, false
 }
 
+// Set store key-value pairs.
+// the kv map is copy-on-write, snapshots returned by Metadata are never modified.
 func (ctx *defaultContext) Set(key string, value any) Context {
 	ctx.Lock()
-	ctx.kv[key] = value
+	kv := make(map[string]any, len(ctx.kv)+1)
+	for k, v := range ctx.kv {
+		kv[k] = v
+	}
+	kv[key] = value
+	ctx.kv = kv
 	ctx.Unlock()
 	return ctx
 }
@@ -92,6 +106,33 @@ func (ctx *defaultContext) Get(key string) (value any, exists bool) {
 	return
 }
 
+// Metadata get a read-only snapshot of the stored key-value pairs
+func (ctx *defaultContext) Metadata() map[string]any {
+	ctx.RLock()
+	defer ctx.RUnlock()
+	return ctx.kv
+}
+
+func (ctx *defaultContext) AddWarning(warning Warning) {
+	if warning == nil {
+		return
+	}
+	ctx.Lock()
+	ctx.warnings = append(ctx.warnings, warning)
+	ctx.Unlock()
+}
+
+func (ctx *defaultContext) Warnings() []Warning {
+	ctx.RLock()
+	defer ctx.RUnlock()
+	if len(ctx.warnings) == 0 {
+		return nil
+	}
+	out := make([]Warning, len(ctx.warnings))
+	copy(out, ctx.warnings)
+	return out
+}
+
 func (ctx *defaultContext) Deadline() (deadline time.Time, ok bool) {
 	ctx.RLock()
 	defer ctx.RUnlock()
@@ -5,6 +5,13 @@ import (
 	"time"
 )
 
+// Warning a non-fatal warning attached to the context,
+// e.g. a warning status created by bstatus.NewWarning.
+type Warning interface {
+	// Reason get the description of the warning
+	Reason() string
+}
+
 // Context extension interface of context.Context
 // Context's methods may be called by multiple goroutines simultaneously.
 type Context interface {
@@ -21,6 +28,15 @@ type Context interface {
 	Set(key string, value any) Context
 	// Get fetch the stored value by key
 	Get(key string) (value any, exists bool)
+	// Metadata get a snapshot of the stored key-value pairs,
+	// e.g. to propagate them to downstream calls.
+	// the snapshot is not affected by later Set calls and must not be modified.
+	Metadata() map[string]any
+	// AddWarning attach a non-fatal warning to the context,
+	// e.g. to be surfaced alongside a successful response.
+	AddWarning(warning Warning)
+	// Warnings get the attached warnings
+	Warnings() []Warning
 
 	/*
 	   The following methods are consistent with the
@@ -2,10 +2,14 @@ package bcontext_test
 
 import (
 	"context"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/lamber92/go-brick/bcontext"
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/lamber92/go-brick/berror/bstatus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -80,8 +84,8 @@ func TestNewWithCtxTimeout(t *testing.T) {
 	begin := time.Now()
 	sec := time.Second * 5
 
-	orig := context.Background()
-	orig, _ = context.WithTimeout(orig, sec)
+	orig, cancel := context.WithTimeout(context.Background(), sec)
+	defer cancel()
 
 	// test Err()
 	ctx := bcontext.NewWithCtx(orig)
@@ -167,3 +171,92 @@ func TestCtxValue(t *testing.T) {
 	assert.Equal(t, ctx, ctx3)
 	assert.Equal(t, value, ctx3.Value(key))
 }
+
+func TestConcurrentAccess(t *testing.T) {
+	ctx := bcontext.New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := strconv.Itoa(i % 4)
+			for j := 0; j < 1000; j++ {
+				ctx.Set(key, j)
+				_, _ = ctx.Get(key)
+				_ = ctx.Value(key)
+				if j%100 == 0 {
+					ctx.WithTimeout(time.Second)
+					_, _ = ctx.Deadline()
+					ctx.Cancel()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 4; i++ {
+		_, ok := ctx.Get(strconv.Itoa(i))
+		assert.Equal(t, true, ok)
+	}
+}
+
+func TestConcurrentAccessWithCtx(t *testing.T) {
+	orig, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ctx := bcontext.NewWithCtx(orig)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					if i%2 == 0 {
+						ctx.WithTimeout(time.Second)
+					} else {
+						ctx.WithCancel()
+					}
+					_ = ctx.Done()
+					_ = ctx.Err()
+				}
+			}(i)
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 10):
+		t.Fatal("overriding the timeout of a context created by NewWithCtx deadlocks")
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	ctx := bcontext.New()
+	assert.Empty(t, ctx.Warnings())
+
+	w1 := bstatus.NewWarning(bcode.OK, "field 'name' is deprecated", nil)
+	w2 := bstatus.NewWarning(bcode.OK, "field 'age' is deprecated", nil)
+	ctx.AddWarning(w1)
+	ctx.AddWarning(nil)
+	ctx.AddWarning(w2)
+	assert.Equal(t, []bcontext.Warning{w1, w2}, ctx.Warnings())
+}
+
+func TestMetadata(t *testing.T) {
+	ctx := bcontext.New()
+	assert.Equal(t, map[string]any{}, ctx.Metadata())
+
+	ctx.Set("trace_id", "abc").Set("tenant", "t1")
+	snapshot := ctx.Metadata()
+	assert.Equal(t, map[string]any{"trace_id": "abc", "tenant": "t1"}, snapshot)
+
+	// later writes do not affect the snapshot
+	ctx.Set("trace_id", "def")
+	assert.Equal(t, "abc", snapshot["trace_id"])
+	v, _ := ctx.Get("trace_id")
+	assert.Equal(t, "def", v)
+}
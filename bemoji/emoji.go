@@ -1,6 +1,11 @@
 package bemoji
 
-import "github.com/lamber92/go-brick/bemoji/official"
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lamber92/go-brick/bemoji/official"
+)
 
 // HasEmoji Check if emoji exists in the string
 func HasEmoji(s string) bool {
@@ -11,3 +16,72 @@ func HasEmoji(s string) bool {
 func FindEmojiPrefix(s string) ([]rune, bool) {
 	return official.AllSequences.FindEmojiPrefix(s)
 }
+
+// RemoveEmoji Remove all emoji(including ZWJ-joined and variation-selector forms) from the string,
+// the other characters are kept as they are
+func RemoveEmoji(s string) string {
+	return ReplaceEmoji(s, func([]rune) string { return "" })
+}
+
+// EmojiMatch the emoji matched in the string
+type EmojiMatch struct {
+	Emoji []rune // the matched emoji sequence
+	Index int    // the starting rune index in the string
+}
+
+// FindAllEmoji Find and return all emoji in the string from left to right,
+// multi-codepoint sequences(e.g. flags and ZWJ-joined families) are returned as single matches
+func FindAllEmoji(s string) []EmojiMatch {
+	out := make([]EmojiMatch, 0)
+	index := 0
+	scanEmoji(s, func(text string, emoji bool) {
+		r := []rune(text)
+		if emoji {
+			out = append(out, EmojiMatch{Emoji: r, Index: index})
+		}
+		index += len(r)
+	})
+	return out
+}
+
+// CountEmoji Count the emoji in the string,
+// multi-codepoint sequences(e.g. keycaps and flags) are counted as one
+func CountEmoji(s string) int {
+	count := 0
+	scanEmoji(s, func(_ string, emoji bool) {
+		if emoji {
+			count++
+		}
+	})
+	return count
+}
+
+// ReplaceEmoji Replace every emoji in the string with the result of @repl,
+// the other characters are kept as they are
+func ReplaceEmoji(s string, repl func(emoji []rune) string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	scanEmoji(s, func(text string, emoji bool) {
+		if emoji {
+			sb.WriteString(repl([]rune(text)))
+			return
+		}
+		sb.WriteString(text)
+	})
+	return sb.String()
+}
+
+// scanEmoji split the string from left to right into the emoji sequences and the single other characters,
+// calling @fn with each piece in order.
+func scanEmoji(s string, fn func(text string, emoji bool)) {
+	for i := 0; i < len(s); {
+		if n := official.AllSequences.MatchEmoji(s[i:]); n > 0 {
+			fn(s[i:i+n], true)
+			i += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		fn(s[i:i+size], false)
+		i += size
+	}
+}
@@ -59,3 +59,66 @@ func TestFindEmojiPrefix(t *testing.T) {
 		t.Logf("expected: %x, actual: %x", expected.Emoji, emoji)
 	}
 }
+
+func TestRemoveEmoji(t *testing.T) {
+	testDataGroupResult := []string{
+		"",
+		"我真的会谢",
+		"这本书一些问题",
+		testDataGroup[3],
+		testDataGroup[4],
+		"11111",
+		"O(∩_∩)O哈哈~",
+		"",
+		"是吗？",
+	}
+	for i, v := range testDataGroup {
+		assert.Equal(t, testDataGroupResult[i], bemoji.RemoveEmoji(v), "Expected results do not match actual results. [%v]", v)
+	}
+}
+
+func TestFindAllEmoji(t *testing.T) {
+	matches := bemoji.FindAllEmoji("👩‍👩‍👦🇨🇳")
+	assert.Equal(t, []bemoji.EmojiMatch{
+		{Emoji: []rune("👩‍👩‍👦"), Index: 0},
+		{Emoji: []rune("🇨🇳"), Index: 5},
+	}, matches)
+
+	matches = bemoji.FindAllEmoji("是吗？🛢️")
+	assert.Equal(t, []bemoji.EmojiMatch{{Emoji: []rune("🛢️"), Index: 3}}, matches)
+
+	assert.Equal(t, []bemoji.EmojiMatch{}, bemoji.FindAllEmoji(testDataGroup[4]))
+}
+
+func TestCountEmoji(t *testing.T) {
+	testDataGroupResult := []int{1, 1, 1, 0, 0, 1, 0, 2, 1}
+	for i, v := range testDataGroup {
+		assert.Equal(t, testDataGroupResult[i], bemoji.CountEmoji(v), "Expected results do not match actual results. [%v]", v)
+		assert.Equal(t, len(bemoji.FindAllEmoji(v)), bemoji.CountEmoji(v))
+	}
+}
+
+func TestReplaceEmoji(t *testing.T) {
+	placeholder := func([]rune) string { return ":emoji:" }
+	assert.Equal(t, ":emoji::emoji:", bemoji.ReplaceEmoji("👩‍👩‍👦🇨🇳", placeholder))
+	assert.Equal(t, "这本书:emoji:一些问题", bemoji.ReplaceEmoji("这本书🈶️一些问题", placeholder))
+
+	remove := func([]rune) string { return "" }
+	for _, v := range testDataGroup {
+		assert.Equal(t, bemoji.RemoveEmoji(v), bemoji.ReplaceEmoji(v, remove), "Expected results do not match actual results. [%v]", v)
+	}
+
+	// the replaced sequences are the same as the ones found
+	for _, v := range testDataGroup {
+		replaced := make([][]rune, 0)
+		bemoji.ReplaceEmoji(v, func(emoji []rune) string {
+			replaced = append(replaced, emoji)
+			return ""
+		})
+		found := make([][]rune, 0)
+		for _, m := range bemoji.FindAllEmoji(v) {
+			found = append(found, m.Emoji)
+		}
+		assert.Equal(t, found, replaced, v)
+	}
+}
@@ -4,6 +4,8 @@
 // Although there are some changes, the underlying implementation is consistent
 package official
 
+import "unicode/utf8"
+
 // sequences is the collection of Sequence type
 type sequences map[rune]*Sequence
 
@@ -128,3 +130,32 @@ func (seq sequences) markEmoji(r []rune, offset *int) bool {
 	// Not in the expression library to prove that it is not an emoji
 	return false
 }
+
+// MatchEmoji returns the byte length of the longest emoji sequence at the beginning of the string,
+// e.g. a whole ZWJ-joined family rather than its first member. 0 if there is no match.
+// a dangling variation selector right after the matched sequence is treated as part of it.
+func (seq sequences) MatchEmoji(s string) int {
+	longest := 0
+	next := seq
+	for i, c := range s {
+		sub, exist := next[c]
+		if !exist {
+			break
+		}
+		if sub.End {
+			longest = i + utf8.RuneLen(c)
+		}
+		next = sub.Nexts
+	}
+	if longest > 0 && longest < len(s) {
+		if c, size := utf8.DecodeRuneInString(s[longest:]); isVariationSelector(c) {
+			longest += size
+		}
+	}
+	return longest
+}
+
+// isVariationSelector determine whether the rune is a text/emoji presentation selector
+func isVariationSelector(r rune) bool {
+	return r == 0xfe0e || r == 0xfe0f
+}
@@ -77,6 +77,7 @@ func (d *myConverter) RegisterMapFromHTTPStatusCode(statusCode int, code bcode.C
 
 func TestCustomizedConverter(t *testing.T) {
 	bcode.ReplaceCodeConverter(&myConverter{})
+	defer bcode.ReplaceCodeConverter(bcode.NewDefaultCodeConverter())
 
 	var codeValue int32 = 1000
 
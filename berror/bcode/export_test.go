@@ -0,0 +1,22 @@
+package bcode
+
+// NewDefaultCodeConverter exported for restoring the built-in converter in tests
+var NewDefaultCodeConverter = newDefaultCodeConverter
+
+// ReleaseNamespace unclaim the namespace so that tests can be run repeatedly
+func ReleaseNamespace(ns Namespace) {
+	ns.(*defaultNamespace).release()
+}
+
+// UnregisterName remove the name of the code registered in tests
+func UnregisterName(code Code) {
+	if name, ok := codeToName[code]; ok {
+		delete(nameToCode, name)
+		delete(codeToName, code)
+	}
+}
+
+// UnregisterSLOCategory remove the SLO category of the code registered in tests
+func UnregisterSLOCategory(code Code) {
+	delete(codeToSLOCategory, code)
+}
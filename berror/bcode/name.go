@@ -0,0 +1,60 @@
+package bcode
+
+import "fmt"
+
+// unknownName the name of the unregistered code
+const unknownName = "Unknown"
+
+// codeToName error code to name default mapping relationship
+var codeToName = map[Code]string{
+	Unknown:            "Unknown",
+	OK:                 "OK",
+	InvalidArgument:    "InvalidArgument",
+	Unauthorized:       "Unauthorized",
+	Forbidden:          "Forbidden",
+	NotFound:           "NotFound",
+	RequestTimeout:     "RequestTimeout",
+	ClientClosed:       "ClientClosed",
+	InternalError:      "InternalError",
+	ServiceUnavailable: "ServiceUnavailable",
+	GatewayTimeout:     "GatewayTimeout",
+	AlreadyExists:      "AlreadyExists",
+}
+
+// nameToCode name to error code reverse mapping relationship
+var nameToCode = func() map[string]Code {
+	m := make(map[string]Code, len(codeToName))
+	for code, name := range codeToName {
+		m[name] = code
+	}
+	return m
+}()
+
+// Name get the human-readable name of the error code,
+// returns "Unknown" for the unregistered one
+func Name(code Code) string {
+	if name, ok := codeToName[code]; ok {
+		return name
+	}
+	return unknownName
+}
+
+// FromName get the error code by its registered name
+func FromName(name string) (Code, bool) {
+	code, ok := nameToCode[name]
+	return code, ok
+}
+
+// Register register or overwrite the name of the error code
+// nb. it panics if the name is already registered by another code,
+// this function is NOT goroutine-safe, call it when you initialize the program.
+func Register(code Code, name string) {
+	if owner, ok := nameToCode[name]; ok && owner != code {
+		panic(fmt.Sprintf("bcode: name %q is already registered by code %d", name, owner.ToInt()))
+	}
+	if old, ok := codeToName[code]; ok {
+		delete(nameToCode, old)
+	}
+	codeToName[code] = name
+	nameToCode[name] = code
+}
@@ -0,0 +1,42 @@
+package bcode_test
+
+import (
+	"testing"
+
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultName(t *testing.T) {
+	assert.Equal(t, "InvalidArgument", bcode.Name(bcode.InvalidArgument))
+	assert.Equal(t, "NotFound", bcode.Name(bcode.NotFound))
+	assert.Equal(t, "Unknown", bcode.Name(bcode.New(77777)))
+
+	code, ok := bcode.FromName("GatewayTimeout")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, bcode.GatewayTimeout, code)
+
+	_, ok = bcode.FromName("NoSuchCode")
+	assert.Equal(t, false, ok)
+}
+
+func TestRegisterName(t *testing.T) {
+	code := bcode.New(77001)
+	defer bcode.UnregisterName(code)
+	bcode.Register(code, "QuotaExceeded")
+	assert.Equal(t, "QuotaExceeded", bcode.Name(code))
+	got, ok := bcode.FromName("QuotaExceeded")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, code, got)
+
+	// rename drops the old name
+	bcode.Register(code, "QuotaReached")
+	_, ok = bcode.FromName("QuotaExceeded")
+	assert.Equal(t, false, ok)
+
+	// a name owned by another code is rejected
+	assert.Panics(t, func() { bcode.Register(code, "NotFound") })
+	got, _ = bcode.FromName("NotFound")
+	assert.Equal(t, bcode.NotFound, got)
+	assert.Equal(t, "QuotaReached", bcode.Name(code))
+}
@@ -0,0 +1,84 @@
+package bcode
+
+import "fmt"
+
+// NamespaceSize the number of codes in a namespace, e.g. base 1000 owns 1000-1999
+const NamespaceSize = 1000
+
+// Namespace
+// a range of error codes owned by one service, avoiding collisions in a shared package
+type Namespace interface {
+	// Base get the first code of the namespace
+	Base() int
+	// Register register a named code at base+offset,
+	// @offset must be in [0, NamespaceSize).
+	Register(offset int, name string) Code
+}
+
+type defaultNamespace struct {
+	base  int
+	codes []Code // the codes registered in the namespace
+}
+
+// builtinCodes the preset codes that no namespace may cover
+var builtinCodes = []Code{
+	Unknown, OK, InvalidArgument, Unauthorized, Forbidden, NotFound, RequestTimeout,
+	ClientClosed, InternalError, ServiceUnavailable, GatewayTimeout, AlreadyExists,
+}
+
+// claimedNamespaces the bases of the created namespaces
+var claimedNamespaces = map[int]struct{}{}
+
+// NewNamespace create a namespace owning the codes [base, base+NamespaceSize).
+// nb. it panics if the base is not a multiple of NamespaceSize, is already claimed
+// or the range covers a built-in code, call it when you initialize the program.
+func NewNamespace(base int) Namespace {
+	if base%NamespaceSize != 0 {
+		panic(fmt.Sprintf("bcode: namespace base %d is not a multiple of %d", base, NamespaceSize))
+	}
+	if _, exist := claimedNamespaces[base]; exist {
+		panic(fmt.Sprintf("bcode: namespace base %d is already claimed", base))
+	}
+	for _, code := range builtinCodes {
+		if c := code.ToInt(); c >= base && c < base+NamespaceSize {
+			panic(fmt.Sprintf("bcode: namespace base %d covers the built-in code %d", base, c))
+		}
+	}
+	claimedNamespaces[base] = struct{}{}
+	return &defaultNamespace{base: base}
+}
+
+func (n *defaultNamespace) Base() int {
+	return n.base
+}
+
+// Register register a named code at base+offset.
+// no mapping is registered for the code: it maps to grpc code Unknown and to itself as http-status-code,
+// which is not a valid one, so map it by RegisterMapToHTTPStatusCode before serving it over http.
+// its SLO category can be set by RegisterSLOCategory.
+// nb. it panics if the offset is out of range or the code is already registered,
+// call it when you initialize the program.
+func (n *defaultNamespace) Register(offset int, name string) Code {
+	if offset < 0 || offset >= NamespaceSize {
+		panic(fmt.Sprintf("bcode: offset %d out of namespace range [0, %d)", offset, NamespaceSize))
+	}
+	code := New(n.base + offset)
+	if exist, ok := codeToName[code]; ok {
+		panic(fmt.Sprintf("bcode: code %d is already registered as %q", code.ToInt(), exist))
+	}
+	Register(code, name)
+	n.codes = append(n.codes, code)
+	return code
+}
+
+// release unclaim the namespace and unregister the names of its codes
+func (n *defaultNamespace) release() {
+	for _, code := range n.codes {
+		if name, ok := codeToName[code]; ok {
+			delete(nameToCode, name)
+			delete(codeToName, code)
+		}
+	}
+	n.codes = nil
+	delete(claimedNamespaces, n.base)
+}
@@ -0,0 +1,47 @@
+package bcode_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestNamespace(t *testing.T) {
+	defer bcode.ReplaceCodeConverter(bcode.NewDefaultCodeConverter())
+	serviceA := bcode.NewNamespace(1000)
+	defer bcode.ReleaseNamespace(serviceA)
+	serviceB := bcode.NewNamespace(2000)
+	defer bcode.ReleaseNamespace(serviceB)
+
+	quota := serviceA.Register(1, "A.QuotaExceeded")
+	conflict := serviceB.Register(1, "B.VersionConflict")
+	assert.Equal(t, 1001, quota.ToInt())
+	assert.Equal(t, 2001, conflict.ToInt())
+	assert.Equal(t, "A.QuotaExceeded", bcode.Name(quota))
+	assert.Equal(t, "B.VersionConflict", bcode.Name(conflict))
+
+	// no mapping is registered by default
+	assert.Equal(t, quota.ToInt(), bcode.ToHTTPStatusCode(quota))
+	assert.Equal(t, bcode.SLOExcluded, bcode.GetSLOCategory(quota))
+	bcode.RegisterMapToHTTPStatusCode(quota, http.StatusTooManyRequests)
+	bcode.RegisterMapToGRPCCode(quota, codes.ResourceExhausted)
+	assert.Equal(t, http.StatusTooManyRequests, bcode.ToHTTPStatusCode(quota))
+	assert.Equal(t, codes.ResourceExhausted, bcode.ToGRPCCode(quota))
+
+	// collisions and out of range offsets are rejected
+	assert.Panics(t, func() { serviceA.Register(1, "A.Other") })
+	assert.Panics(t, func() { serviceA.Register(bcode.NamespaceSize, "A.Overflow") })
+}
+
+func TestNamespace_InvalidBase(t *testing.T) {
+	ns := bcode.NewNamespace(3000)
+	defer bcode.ReleaseNamespace(ns)
+
+	// overlapping, already claimed or covering built-in codes
+	assert.Panics(t, func() { bcode.NewNamespace(3500) })
+	assert.Panics(t, func() { bcode.NewNamespace(3000) })
+	assert.Panics(t, func() { bcode.NewNamespace(0) })
+}
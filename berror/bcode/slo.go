@@ -0,0 +1,49 @@
+package bcode
+
+// SLOCategory
+// error code SLO category, used to decide whether an error should be
+// counted against availability. for example, a client 400 should be excluded.
+type SLOCategory int8
+
+const (
+	SLOExcluded  SLOCategory = iota // not counted against availability, e.g. client errors
+	SLOAffecting                    // counted against availability, e.g. server errors
+)
+
+// codeToSLOCategory error code to SLO category default mapping relationship
+var codeToSLOCategory = map[Code]SLOCategory{
+	Unknown:            SLOAffecting,
+	OK:                 SLOExcluded,
+	InvalidArgument:    SLOExcluded,
+	Unauthorized:       SLOExcluded,
+	Forbidden:          SLOExcluded,
+	NotFound:           SLOExcluded,
+	RequestTimeout:     SLOExcluded,
+	ClientClosed:       SLOExcluded,
+	InternalError:      SLOAffecting,
+	ServiceUnavailable: SLOAffecting,
+	GatewayTimeout:     SLOAffecting,
+	AlreadyExists:      SLOExcluded,
+}
+
+// GetSLOCategory get error code SLO category.
+// the unregistered one is classified by its http-status-code: 5xx affects SLO, others don't.
+func GetSLOCategory(code Code) SLOCategory {
+	if v, ok := codeToSLOCategory[code]; ok {
+		return v
+	}
+	if status := ToHTTPStatusCode(code); status >= 500 && status < 600 {
+		return SLOAffecting
+	}
+	return SLOExcluded
+}
+
+// RegisterSLOCategory register or overwrite the SLO category of the error code
+func RegisterSLOCategory(code Code, category SLOCategory) {
+	codeToSLOCategory[code] = category
+}
+
+// ReplaceCodeSLOMapping Replace the default code-SLO-category-mapping with a custom one
+func ReplaceCodeSLOMapping(m map[Code]SLOCategory) {
+	codeToSLOCategory = m
+}
@@ -0,0 +1,30 @@
+package bcode_test
+
+import (
+	"testing"
+
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultSLOCategory(t *testing.T) {
+	assert.Equal(t, bcode.SLOExcluded, bcode.GetSLOCategory(bcode.OK))
+	assert.Equal(t, bcode.SLOExcluded, bcode.GetSLOCategory(bcode.InvalidArgument))
+	assert.Equal(t, bcode.SLOExcluded, bcode.GetSLOCategory(bcode.NotFound))
+	assert.Equal(t, bcode.SLOExcluded, bcode.GetSLOCategory(bcode.AlreadyExists))
+	assert.Equal(t, bcode.SLOAffecting, bcode.GetSLOCategory(bcode.Unknown))
+	assert.Equal(t, bcode.SLOAffecting, bcode.GetSLOCategory(bcode.InternalError))
+	assert.Equal(t, bcode.SLOAffecting, bcode.GetSLOCategory(bcode.ServiceUnavailable))
+	assert.Equal(t, bcode.SLOAffecting, bcode.GetSLOCategory(bcode.GatewayTimeout))
+}
+
+func TestCustomizedSLOCategory(t *testing.T) {
+	code := bcode.New(88888)
+	defer bcode.UnregisterSLOCategory(code)
+	bcode.RegisterSLOCategory(code, bcode.SLOAffecting)
+	assert.Equal(t, bcode.SLOAffecting, bcode.GetSLOCategory(code))
+
+	defer bcode.RegisterSLOCategory(bcode.NotFound, bcode.SLOExcluded)
+	bcode.RegisterSLOCategory(bcode.NotFound, bcode.SLOAffecting)
+	assert.Equal(t, bcode.SLOAffecting, bcode.GetSLOCategory(bcode.NotFound))
+}
@@ -0,0 +1,43 @@
+// Package berrortest provides helpers for asserting brick errors in tests.
+package berrortest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lamber92/go-brick/berror/bcode"
+)
+
+// envelope the fields shared by the serialized Error and PublicError
+type envelope struct {
+	Code   *int   `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// AssertEnvelope decode the standard error envelope(the Error() output or an encoded berror.PublicError)
+// from @responseBody, and assert its code and that its reason contains @wantReasonSubstr.
+// returns whether the assertion passes.
+func AssertEnvelope(t testing.TB, responseBody []byte, wantCode bcode.Code, wantReasonSubstr string) bool {
+	t.Helper()
+
+	var env envelope
+	if err := json.Unmarshal(responseBody, &env); err != nil {
+		t.Errorf("failed to decode error envelope: %v\nbody: %s", err, responseBody)
+		return false
+	}
+	if env.Code == nil {
+		t.Errorf("error envelope has no code\nbody: %s", responseBody)
+		return false
+	}
+	ok := true
+	if *env.Code != wantCode.ToInt() {
+		t.Errorf("unexpected error code\nwant: %d\ngot:  %d\nbody: %s", wantCode.ToInt(), *env.Code, responseBody)
+		ok = false
+	}
+	if !strings.Contains(env.Reason, wantReasonSubstr) {
+		t.Errorf("unexpected error reason\nwant substring: %q\ngot:            %q\nbody: %s", wantReasonSubstr, env.Reason, responseBody)
+		ok = false
+	}
+	return ok
+}
@@ -0,0 +1,65 @@
+package berrortest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/lamber92/go-brick/berror/berrortest"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingTB records the failures reported by the assertions instead of failing the test
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertEnvelope(t *testing.T) {
+	err := berror.NewNotFound(nil, "user 42 not found")
+	berrortest.AssertEnvelope(t, []byte(err.Error()), bcode.NotFound, "not found")
+
+	body, _ := json.Marshal(err.(berror.Error).ToPublicDTO())
+	berrortest.AssertEnvelope(t, body, bcode.NotFound, "user 42")
+}
+
+func TestAssertEnvelope_Failure(t *testing.T) {
+	body := []byte(berror.NewNotFound(nil, "user 42 not found").Error())
+
+	cases := []struct {
+		body       []byte
+		code       bcode.Code
+		reason     string
+		wantReport string
+	}{
+		{body, bcode.InternalError, "not found", "unexpected error code"},
+		{body, bcode.NotFound, "forbidden", "unexpected error reason"},
+		{[]byte("not json"), bcode.NotFound, "", "failed to decode error envelope"},
+		{[]byte(`{"reason":"x"}`), bcode.NotFound, "", "error envelope has no code"},
+	}
+	for _, c := range cases {
+		mock := &recordingTB{TB: t}
+		assert.Equal(t, false, berrortest.AssertEnvelope(mock, c.body, c.code, c.reason))
+		assert.Equal(t, 1, len(mock.failures), c.wantReport)
+		if len(mock.failures) > 0 {
+			assert.Contains(t, mock.failures[0], c.wantReport)
+		}
+	}
+
+	// a passing assertion reports nothing
+	mock := &recordingTB{TB: t}
+	assert.Equal(t, true, berrortest.AssertEnvelope(mock, body, bcode.NotFound, "not found"))
+	assert.Empty(t, mock.failures)
+}
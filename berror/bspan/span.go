@@ -0,0 +1,59 @@
+// Package bspan records brick error chains on OpenTelemetry spans.
+package bspan
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bcode"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxChainDepth the max number of levels followed in the error chain
+const maxChainDepth = 1024
+
+// RecordOnSpan record every level of the error chain as an exception event on the span,
+// and set the span status to Error.
+// a brick level is recorded with its code name(see bcode.Name) as the type, its reason as the message and its stack,
+// the chain is followed through the non-brick wrappers(e.g. fmt.Errorf with %w) by errors.Unwrap,
+// and the innermost non-brick cause is recorded with its go type and Error() output.
+func RecordOnSpan(span trace.Span, err error) {
+	if span == nil || err == nil {
+		return
+	}
+	visited := make(map[error]struct{})
+	for next, depth := err, 0; next != nil && depth < maxChainDepth; next, depth = errors.Unwrap(next), depth+1 {
+		e, ok := next.(berror.Error)
+		if !ok || e.Status() == nil {
+			// skip the wrappers of the brick levels, only the root cause is recorded
+			if inner := errors.Unwrap(next); inner != nil {
+				if _, ok = berror.AsError(inner); ok {
+					continue
+				}
+			}
+			span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+				semconv.ExceptionType(fmt.Sprintf("%T", next)),
+				semconv.ExceptionMessage(next.Error()),
+			))
+			break
+		}
+		if _, exist := visited[e]; exist {
+			break
+		}
+		visited[e] = struct{}{}
+		span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+			semconv.ExceptionType(bcode.Name(e.Status().Code())),
+			semconv.ExceptionMessage(e.Status().Reason()),
+			semconv.ExceptionStacktrace(e.Stack().Error()),
+		))
+	}
+
+	if e, ok := berror.AsError(err); ok && e.Status() != nil {
+		span.SetStatus(codes.Error, e.Status().Reason())
+		return
+	}
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,87 @@
+package bspan_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/lamber92/go-brick/berror/bspan"
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordingSpan records the events and the status set on the span
+type recordingSpan struct {
+	trace.Span
+	events      []event
+	code        codes.Code
+	description string
+}
+
+type event struct {
+	typ     string
+	message string
+}
+
+func newRecordingSpan() *recordingSpan {
+	return &recordingSpan{Span: trace.SpanFromContext(context.Background())}
+}
+
+func (s *recordingSpan) AddEvent(name string, options ...trace.EventOption) {
+	if name != semconv.ExceptionEventName {
+		return
+	}
+	cfg := trace.NewEventConfig(options...)
+	attrs := map[string]string{}
+	for _, attr := range cfg.Attributes() {
+		attrs[string(attr.Key)] = attr.Value.AsString()
+	}
+	s.events = append(s.events, event{
+		typ:     attrs[string(semconv.ExceptionTypeKey)],
+		message: attrs[string(semconv.ExceptionMessageKey)],
+	})
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, description string) {
+	s.code, s.description = code, description
+}
+
+func TestRecordOnSpan(t *testing.T) {
+	err1 := errors.New("xxxx")
+	err2 := fmt.Errorf("err2. %w", err1)
+	err3 := berror.New(bstatus.InternalError, err2)
+	err4 := berror.New(bstatus.New(bcode.NotFound, "yyyy", nil), err3)
+
+	span := newRecordingSpan()
+	bspan.RecordOnSpan(span, err4)
+	assert.Equal(t, codes.Error, span.code)
+	assert.Equal(t, "yyyy", span.description)
+	// err4(NotFound) -> err3(InternalError) -> err2(*fmt.wrapError)
+	assert.Equal(t, []event{
+		{bcode.Name(bcode.NotFound), "yyyy"},
+		{bcode.Name(bcode.InternalError), "Internal Server Error"},
+		{"*fmt.wrapError", "err2. xxxx"},
+	}, span.events)
+
+	// a fmt-wrapped top level is followed to the brick levels
+	span = newRecordingSpan()
+	bspan.RecordOnSpan(span, fmt.Errorf("outer: %w", err4))
+	assert.Equal(t, "yyyy", span.description)
+	assert.Equal(t, []event{
+		{bcode.Name(bcode.NotFound), "yyyy"},
+		{bcode.Name(bcode.InternalError), "Internal Server Error"},
+		{"*fmt.wrapError", "err2. xxxx"},
+	}, span.events)
+
+	// a plain error is recorded as it is
+	span = newRecordingSpan()
+	bspan.RecordOnSpan(span, err1)
+	assert.Equal(t, "xxxx", span.description)
+	assert.Equal(t, []event{{"*errors.errorString", "xxxx"}}, span.events)
+}
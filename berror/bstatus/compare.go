@@ -0,0 +1,16 @@
+package bstatus
+
+import "reflect"
+
+// Equal determine whether the two statuses are equivalent,
+// comparing the code and reason strictly and the detail with reflect.DeepEqual.
+// two nil statuses are equal.
+func Equal(a, b Status) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.Code().ToInt() != b.Code().ToInt() || a.Reason() != b.Reason() {
+		return false
+	}
+	return reflect.DeepEqual(a.Detail(), b.Detail())
+}
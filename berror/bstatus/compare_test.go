@@ -0,0 +1,23 @@
+package bstatus_test
+
+import (
+	"testing"
+
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqual(t *testing.T) {
+	a := bstatus.New(bcode.InvalidArgument, "bad request", map[string]any{"field": "name"})
+	b := bstatus.New(bcode.InvalidArgument, "bad request", map[string]any{"field": "name"})
+	assert.Equal(t, true, bstatus.Equal(a, b))
+	assert.Equal(t, true, bstatus.Equal(bstatus.NotFound, bstatus.New(bcode.NotFound, "Resource Not Found", nil)))
+
+	assert.Equal(t, false, bstatus.Equal(a, bstatus.New(bcode.InvalidArgument, "bad request", map[string]any{"field": "age"})))
+	assert.Equal(t, false, bstatus.Equal(a, bstatus.New(bcode.InvalidArgument, "other", map[string]any{"field": "name"})))
+	assert.Equal(t, false, bstatus.Equal(a, bstatus.New(bcode.NotFound, "bad request", map[string]any{"field": "name"})))
+
+	assert.Equal(t, true, bstatus.Equal(nil, nil))
+	assert.Equal(t, false, bstatus.Equal(a, nil))
+}
@@ -0,0 +1,6 @@
+package bstatus
+
+// UnregisterTemplate remove the template registered in tests
+func UnregisterTemplate(msgID string) {
+	messageTemplates.Delete(msgID)
+}
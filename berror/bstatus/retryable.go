@@ -0,0 +1,59 @@
+package bstatus
+
+import "github.com/lamber92/go-brick/berror/bcode"
+
+// retryableStatus
+// status carrier explicitly marked as retryable or not,
+// overriding whether its code is retryable by default
+type retryableStatus struct {
+	defaultStatus
+	retryable bool
+}
+
+// NewRetryable create a status marked as retryable,
+// regardless of whether its code is retryable by default.
+func NewRetryable(code bcode.Code, reason string, detail any) Status {
+	return &retryableStatus{
+		defaultStatus: defaultStatus{
+			code:   code,
+			reason: reason,
+			detail: detail,
+		},
+		retryable: true,
+	}
+}
+
+// NewNonRetryable create a status marked as not retryable,
+// regardless of whether its code is retryable by default,
+// e.g. an operation that has already exhausted its retries.
+func NewNonRetryable(code bcode.Code, reason string, detail any) Status {
+	return &retryableStatus{
+		defaultStatus: defaultStatus{
+			code:   code,
+			reason: reason,
+			detail: detail,
+		},
+		retryable: false,
+	}
+}
+
+func (c *retryableStatus) withDetail(detail any) Status {
+	cp := *c
+	cp.detail = detail
+	return &cp
+}
+
+func (c *retryableStatus) Retryable() bool {
+	return c.retryable
+}
+
+// IsMarkedRetryable determine whether the status is explicitly marked as retryable or not.
+// returns the mark and whether the status carries one.
+func IsMarkedRetryable(status Status) (retryable bool, marked bool) {
+	if r, ok := status.(interface {
+		Retryable() bool
+	}); ok {
+		return r.Retryable(), true
+	}
+	return false, false
+}
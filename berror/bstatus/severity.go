@@ -0,0 +1,56 @@
+package bstatus
+
+import "github.com/lamber92/go-brick/berror/bcode"
+
+// Severity
+// status severity, a warning status does not fail the response.
+type Severity int8
+
+const (
+	SeverityError   Severity = iota // the status fails the request
+	SeverityWarning                 // the status is a non-fatal warning attached to a successful response
+)
+
+// warningStatus
+// status carrier with warning severity
+type warningStatus struct {
+	defaultStatus
+}
+
+// NewWarning create a non-fatal warning status,
+// e.g. a deprecated field is used but the request still succeeds.
+func NewWarning(code bcode.Code, reason string, detail any) Status {
+	return &warningStatus{
+		defaultStatus: defaultStatus{
+			code:   code,
+			reason: reason,
+			detail: detail,
+		},
+	}
+}
+
+func (c *warningStatus) withDetail(detail any) Status {
+	cp := *c
+	cp.detail = detail
+	return &cp
+}
+
+func (c *warningStatus) Severity() Severity {
+	return SeverityWarning
+}
+
+// GetSeverity get the severity of the status.
+// statuses without severity are treated as errors.
+func GetSeverity(status Status) Severity {
+	if s, ok := status.(interface {
+		Severity() Severity
+	}); ok {
+		return s.Severity()
+	}
+	return SeverityError
+}
+
+// IsWarning determine whether the status is a non-fatal warning.
+func IsWarning(status Status) bool {
+	return GetSeverity(status) == SeverityWarning
+}
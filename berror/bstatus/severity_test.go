@@ -0,0 +1,35 @@
+package bstatus_test
+
+import (
+	"testing"
+
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverity(t *testing.T) {
+	warning := bstatus.NewWarning(bcode.OK, "field 'name' is deprecated", nil)
+	assert.Equal(t, bstatus.SeverityWarning, bstatus.GetSeverity(warning))
+	assert.Equal(t, true, bstatus.IsWarning(warning))
+	assert.Equal(t, bcode.OK, warning.Code())
+	assert.Equal(t, "field 'name' is deprecated", warning.Reason())
+
+	assert.Equal(t, bstatus.SeverityError, bstatus.GetSeverity(bstatus.InternalError))
+	assert.Equal(t, false, bstatus.IsWarning(bstatus.New(bcode.NotFound, "not found", nil)))
+}
+
+func TestNewRetryable(t *testing.T) {
+	st := bstatus.NewRetryable(bcode.InternalError, "lock conflict", nil)
+	retryable, marked := bstatus.IsMarkedRetryable(st)
+	assert.Equal(t, true, retryable)
+	assert.Equal(t, true, marked)
+	assert.Equal(t, bcode.InternalError, st.Code())
+
+	retryable, marked = bstatus.IsMarkedRetryable(bstatus.NewNonRetryable(bcode.GatewayTimeout, "gave up", nil))
+	assert.Equal(t, false, retryable)
+	assert.Equal(t, true, marked)
+
+	_, marked = bstatus.IsMarkedRetryable(bstatus.InternalError)
+	assert.Equal(t, false, marked)
+}
@@ -67,6 +67,38 @@ func (c *defaultStatus) String() string {
 	return fmt.Sprintf("[%d]", c.code)
 }
 
+// WithDetail returns a copy of the status with the detail replaced,
+// keeping the concrete status type, e.g. the retryable, warning and template markers.
+// a custom status type is wrapped and only its Detail() is overridden.
+func WithDetail(status Status, detail any) Status {
+	if status == nil {
+		return nil
+	}
+	if s, ok := status.(interface {
+		withDetail(detail any) Status
+	}); ok {
+		return s.withDetail(detail)
+	}
+	return &detailStatus{Status: status, detail: detail}
+}
+
+func (c *defaultStatus) withDetail(detail any) Status {
+	cp := *c
+	cp.detail = detail
+	return &cp
+}
+
+// detailStatus
+// wraps a custom status to override its detail
+type detailStatus struct {
+	Status
+	detail any
+}
+
+func (c *detailStatus) Detail() any {
+	return c.detail
+}
+
 // =======================================
 // ----- Default Internal Status Hub -----
 // =======================================
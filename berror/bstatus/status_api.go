@@ -8,3 +8,11 @@ type Status interface {
 	Reason() string   // error description
 	Detail() any      // error extension
 }
+
+// TemplateStatus Carrier of business error info with a message template,
+// the raw message id and arguments are available for client-side localization.
+type TemplateStatus interface {
+	Status
+	MessageID() string    // message template id
+	Args() map[string]any // message template arguments
+}
@@ -0,0 +1,84 @@
+package bstatus
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/lamber92/go-brick/berror/bcode"
+)
+
+// messageTemplates registered default templates, msgID -> template.
+var messageTemplates = sync.Map{}
+
+// RegisterTemplate register the default template of the message id.
+// placeholders in the template are written as {name} and replaced with the argument of the same name.
+func RegisterTemplate(msgID string, template string) {
+	messageTemplates.Store(msgID, template)
+}
+
+// templateStatus
+// status carrying a message template and its arguments, so that the client can localize the reason.
+type templateStatus struct {
+	code   bcode.Code
+	msgID  string
+	args   map[string]any
+	detail any
+}
+
+// NewTemplate create a status whose reason is rendered from the message template.
+// the default template is the registered one of @msgID, or @msgID itself if not registered.
+func NewTemplate(code bcode.Code, msgID string, args map[string]any) TemplateStatus {
+	return &templateStatus{
+		code:  code,
+		msgID: msgID,
+		args:  args,
+	}
+}
+
+func (c *templateStatus) Code() bcode.Code {
+	return c.code
+}
+
+// Reason returns the reason rendered from the default template
+func (c *templateStatus) Reason() string {
+	template := c.msgID
+	if v, ok := messageTemplates.Load(c.msgID); ok {
+		template = v.(string)
+	}
+	return renderTemplate(template, c.args)
+}
+
+func (c *templateStatus) Detail() any {
+	return c.detail
+}
+
+func (c *templateStatus) withDetail(detail any) Status {
+	cp := *c
+	cp.detail = detail
+	return &cp
+}
+
+func (c *templateStatus) MessageID() string {
+	return c.msgID
+}
+
+func (c *templateStatus) Args() map[string]any {
+	return c.args
+}
+
+func (c *templateStatus) String() string {
+	return fmt.Sprintf("[%d]:%s", c.code, c.Reason())
+}
+
+// renderTemplate replace the {name} placeholders in the template with the arguments.
+func renderTemplate(template string, args map[string]any) string {
+	if len(args) == 0 {
+		return template
+	}
+	pairs := make([]string, 0, len(args)*2)
+	for k, v := range args {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
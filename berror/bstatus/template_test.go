@@ -0,0 +1,36 @@
+package bstatus_test
+
+import (
+	"testing"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTemplate(t *testing.T) {
+	args := map[string]any{"name": "lamber", "id": 42}
+
+	// unregistered message id is used as the template
+	st := bstatus.NewTemplate(bcode.NotFound, "user {name}({id}) not found", args)
+	assert.Equal(t, bcode.NotFound, st.Code())
+	assert.Equal(t, "user lamber(42) not found", st.Reason())
+	assert.Equal(t, "user {name}({id}) not found", st.MessageID())
+	assert.Equal(t, args, st.Args())
+
+	// registered default template
+	bstatus.RegisterTemplate("user.not_found", "user {name} does not exist")
+	defer bstatus.UnregisterTemplate("user.not_found")
+	st2 := bstatus.NewTemplate(bcode.NotFound, "user.not_found", args)
+	assert.Equal(t, "user lamber does not exist", st2.Reason())
+	assert.Equal(t, "user.not_found", st2.MessageID())
+
+	// rendered in the error output
+	err := berror.New(st2)
+	assert.Contains(t, err.Error(), `"reason":"user lamber does not exist"`)
+	raw, ok := err.Status().(bstatus.TemplateStatus)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "user.not_found", raw.MessageID())
+	assert.Equal(t, args, raw.Args())
+}
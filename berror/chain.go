@@ -0,0 +1,32 @@
+package berror
+
+import "errors"
+
+const (
+	// cycleMarker rendered in place of the nested error when the chain loops back
+	cycleMarker = "<cycle>"
+	// maxChainDepth the max number of levels followed when looking for a cycle
+	maxChainDepth = 1024
+)
+
+// visitedSet the *defaultError levels on the path from the outermost level to the current one,
+// used to break reference cycles in chains. levels are removed on return,
+// so siblings of a joined error sharing the same inner error are not taken as a cycle.
+type visitedSet map[*defaultError]struct{}
+
+// reaches determine whether following the unwrap chain of err leads back to a visited level.
+// a chain deeper than maxChainDepth is also treated as a cycle.
+func (vs visitedSet) reaches(err error) bool {
+	for i := 0; err != nil; i++ {
+		if i >= maxChainDepth {
+			return true
+		}
+		if d, ok := err.(*defaultError); ok {
+			if _, exist := vs[d]; exist {
+				return true
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
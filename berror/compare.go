@@ -0,0 +1,59 @@
+package berror
+
+import (
+	"reflect"
+
+	"github.com/lamber92/go-brick/berror/bstatus"
+)
+
+// EqualIgnoring determine whether the two error chains are equivalent,
+// comparing the code, reason and detail of each level,
+// while the detail keys listed in @ignoreDetailKeys are ignored (only for map[string]any details).
+// non-Error levels are compared by their Error() output.
+// it is useful to keep table-driven tests stable when details contain timestamps or ids.
+func EqualIgnoring(a, b error, ignoreDetailKeys ...string) bool {
+	for depth := 0; depth < maxChainDepth; depth++ {
+		if a == nil || b == nil {
+			return a == nil && b == nil
+		}
+		ea, ok1 := a.(Error)
+		eb, ok2 := b.(Error)
+		if ok1 != ok2 {
+			return false
+		}
+		if !ok1 {
+			return a.Error() == b.Error()
+		}
+		if !statusEqualIgnoring(ea.Status(), eb.Status(), ignoreDetailKeys) {
+			return false
+		}
+		a, b = ea.Cause(), eb.Cause()
+	}
+	return false
+}
+
+func statusEqualIgnoring(a, b bstatus.Status, ignoreDetailKeys []string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.Code().ToInt() != b.Code().ToInt() || a.Reason() != b.Reason() {
+		return false
+	}
+	return reflect.DeepEqual(omitDetailKeys(a.Detail(), ignoreDetailKeys), omitDetailKeys(b.Detail(), ignoreDetailKeys))
+}
+
+// omitDetailKeys returns a copy of the map[string]any detail without the keys.
+func omitDetailKeys(detail any, keys []string) any {
+	m, ok := detail.(map[string]any)
+	if !ok || len(keys) == 0 {
+		return detail
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	for _, k := range keys {
+		delete(out, k)
+	}
+	return out
+}
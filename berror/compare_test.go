@@ -0,0 +1,36 @@
+package berror_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqualIgnoring(t *testing.T) {
+	newErr := func(timestamp int64, id int) error {
+		root := errors.New("root")
+		return berror.New(bstatus.New(bcode.NotFound, "not found", map[string]any{"timestamp": timestamp, "id": id}), root)
+	}
+
+	assert.Equal(t, true, berror.EqualIgnoring(newErr(1, 7), newErr(2, 7), "timestamp"))
+	assert.Equal(t, false, berror.EqualIgnoring(newErr(1, 7), newErr(2, 7)))
+	assert.Equal(t, false, berror.EqualIgnoring(newErr(1, 7), newErr(1, 8), "timestamp"))
+
+	// different code or reason
+	err1 := berror.NewNotFound(nil, "not found")
+	err2 := berror.NewAlreadyExists(nil, "not found")
+	err3 := berror.NewNotFound(nil, "missing")
+	assert.Equal(t, false, berror.EqualIgnoring(err1, err2))
+	assert.Equal(t, false, berror.EqualIgnoring(err1, err3))
+
+	// different chain
+	err4 := berror.NewNotFound(errors.New("cause"), "not found")
+	assert.Equal(t, false, berror.EqualIgnoring(err1, err4))
+
+	assert.Equal(t, true, berror.EqualIgnoring(nil, nil))
+	assert.Equal(t, false, berror.EqualIgnoring(err1, nil))
+}
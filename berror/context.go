@@ -0,0 +1,28 @@
+package berror
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lamber92/go-brick/berror/bstatus"
+)
+
+// FromContextError convert the error returned by ctx.Err() into a brick error,
+// context.DeadlineExceeded is mapped to GatewayTimeout and context.Canceled to ClientClosed.
+// returns nil for nil, the error itself if it is already an Error,
+// otherwise the error is wrapped as InternalError.
+func FromContextError(err error) Error {
+	if isNilError(err) {
+		return nil
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return NewWithSkip(err, bstatus.GatewayTimeout, 1)
+	case errors.Is(err, context.Canceled):
+		return NewWithSkip(err, bstatus.ClientClosed, 1)
+	}
+	if e, ok := err.(Error); ok {
+		return e
+	}
+	return NewWithSkip(err, bstatus.InternalError, 1)
+}
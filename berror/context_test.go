@@ -0,0 +1,29 @@
+package berror_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContextError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	err := berror.FromContextError(ctx.Err())
+	assert.Equal(t, bcode.GatewayTimeout, err.Status().Code())
+	assert.Equal(t, true, errors.Is(err, context.DeadlineExceeded))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = berror.FromContextError(ctx.Err())
+	assert.Equal(t, bcode.ClientClosed, err.Status().Code())
+	assert.Equal(t, true, errors.Is(err, context.Canceled))
+
+	assert.Nil(t, berror.FromContextError(nil))
+}
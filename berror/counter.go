@@ -0,0 +1,51 @@
+package berror
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/lamber92/go-brick/berror/bcode"
+)
+
+var (
+	// countingEnabled whether to count the created errors by code
+	countingEnabled atomic.Bool
+	// codeCounts bcode.Code -> *uint64
+	codeCounts = sync.Map{}
+)
+
+// EnableCounting set whether to count the created errors by code.
+// it provides a zero-dependency metrics view, see CodeCounts.
+func EnableCounting(enable bool) {
+	countingEnabled.Store(enable)
+}
+
+// CodeCounts returns a snapshot of the number of created errors by code.
+func CodeCounts() map[bcode.Code]uint64 {
+	out := make(map[bcode.Code]uint64)
+	codeCounts.Range(func(key, value any) bool {
+		out[key.(bcode.Code)] = atomic.LoadUint64(value.(*uint64))
+		return true
+	})
+	return out
+}
+
+// ResetCodeCounts clear the counted numbers.
+func ResetCodeCounts() {
+	codeCounts.Range(func(key, _ any) bool {
+		codeCounts.Delete(key)
+		return true
+	})
+}
+
+// countCode increase the number of created errors of the code
+func countCode(code bcode.Code) {
+	if !countingEnabled.Load() || code == nil {
+		return
+	}
+	v, ok := codeCounts.Load(code)
+	if !ok {
+		v, _ = codeCounts.LoadOrStore(code, new(uint64))
+	}
+	atomic.AddUint64(v.(*uint64), 1)
+}
@@ -0,0 +1,33 @@
+package berror_test
+
+import (
+	"testing"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeCounts(t *testing.T) {
+	berror.EnableCounting(true)
+	defer berror.EnableCounting(false)
+	berror.ResetCodeCounts()
+	defer berror.ResetCodeCounts()
+
+	_ = berror.NewNotFound(nil, "not found 1")
+	_ = berror.NewNotFound(nil, "not found 2")
+	_ = berror.NewInternalError(nil, "internal")
+
+	counts := berror.CodeCounts()
+	assert.Equal(t, uint64(2), counts[bcode.NotFound])
+	assert.Equal(t, uint64(1), counts[bcode.InternalError])
+	assert.Equal(t, uint64(0), counts[bcode.InvalidArgument])
+
+	berror.ResetCodeCounts()
+	assert.Equal(t, 0, len(berror.CodeCounts()))
+
+	// counting is opt-in
+	berror.EnableCounting(false)
+	_ = berror.NewNotFound(nil, "not counted")
+	assert.Equal(t, 0, len(berror.CodeCounts()))
+}
@@ -0,0 +1,169 @@
+package berror
+
+import (
+	"bytes"
+	"encoding/json"
+	"unicode/utf8"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const truncatedMarker = "...(truncated)"
+
+// formatDetail returns the detail used for serialization.
+func formatDetail(detail any) any {
+	detail = limitDetailDepth(detail)
+	if str, ok := truncateDetail(detail); ok {
+		return str
+	}
+	return detail
+}
+
+// addDetail add the detail to the log encoder,
+// keeping the output consistent with the serialization of Error().
+func addDetail(enc zapcore.ObjectEncoder, key string, detail any) {
+	detail = limitDetailDepth(detail)
+	if str, ok := truncateDetail(detail); ok {
+		enc.AddString(key, str)
+		return
+	}
+	switch tmp := detail.(type) {
+	case zapcore.ObjectMarshaler:
+		_ = enc.AddObject(key, tmp)
+	case json.Marshaler:
+		// decode the customized JSON, so that every encoder renders the same structure as Error()
+		if v, ok := decodeMarshaledDetail(tmp); ok {
+			_ = enc.AddReflected(key, v)
+			return
+		}
+		_ = enc.AddReflected(key, detail)
+	default:
+		_ = enc.AddReflected(key, detail)
+	}
+}
+
+// formatDetails returns the detail objects used for serialization, nil if empty.
+func formatDetails(details []any) []any {
+	if len(details) == 0 {
+		return nil
+	}
+	out := make([]any, 0, len(details))
+	for _, detail := range details {
+		out = append(out, formatDetail(detail))
+	}
+	return out
+}
+
+// addDetails add the detail objects to the log encoder as an array,
+// each element is encoded the same as addDetail.
+func addDetails(enc zapcore.ObjectEncoder, key string, details []any) {
+	_ = enc.AddArray(key, zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		for _, detail := range details {
+			appendDetail(enc, detail)
+		}
+		return nil
+	}))
+}
+
+func appendDetail(enc zapcore.ArrayEncoder, detail any) {
+	detail = limitDetailDepth(detail)
+	if str, ok := truncateDetail(detail); ok {
+		enc.AppendString(str)
+		return
+	}
+	switch tmp := detail.(type) {
+	case zapcore.ObjectMarshaler:
+		_ = enc.AppendObject(tmp)
+	case json.Marshaler:
+		if v, ok := decodeMarshaledDetail(tmp); ok {
+			_ = enc.AppendReflected(v)
+			return
+		}
+		_ = enc.AppendReflected(detail)
+	default:
+		_ = enc.AppendReflected(detail)
+	}
+}
+
+// decodeMarshaledDetail decode the customized JSON of the detail into a generic value,
+// numbers are kept as json.Number so that large integers are not rounded to float64.
+func decodeMarshaledDetail(m json.Marshaler) (any, bool) {
+	raw, err := m.MarshalJSON()
+	if err != nil {
+		return nil, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err = dec.Decode(&v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// truncateDetail serialize the detail and truncate it if it exceeds maxDetailSize.
+// returns false if the detail does not need to be truncated.
+func truncateDetail(detail any) (string, bool) {
+	if maxDetailSize <= 0 || detail == nil {
+		return "", false
+	}
+	raw, err := jsonStdIter.Marshal(detail)
+	if err != nil || len(raw) <= maxDetailSize {
+		return "", false
+	}
+	// do not cut in the middle of a multibyte character
+	end := maxDetailSize
+	for end > 0 && !utf8.RuneStart(raw[end]) {
+		end--
+	}
+	return string(raw[:end]) + truncatedMarker, true
+}
+
+// mergeGlobalDetail merge the global detail into the detail.
+// only nil and map[string]any details are merged, the per-error keys take precedence.
+func mergeGlobalDetail(detail any) any {
+	if len(globalDetail) == 0 {
+		return detail
+	}
+	var own map[string]any
+	switch tmp := detail.(type) {
+	case nil:
+	case map[string]any:
+		own = tmp
+	default:
+		return detail
+	}
+	out := make(map[string]any, len(globalDetail)+len(own))
+	for k, v := range globalDetail {
+		out[k] = v
+	}
+	for k, v := range own {
+		out[k] = v
+	}
+	return out
+}
+
+// mergeDetail merge the inner detail with the outer detail.
+// map[string]any details are merged with the outer keys taking precedence,
+// otherwise the non-nil outer detail wins.
+func mergeDetail(inner, outer any) any {
+	if inner == nil {
+		return outer
+	}
+	if outer == nil {
+		return inner
+	}
+	innerMap, ok1 := inner.(map[string]any)
+	outerMap, ok2 := outer.(map[string]any)
+	if !ok1 || !ok2 {
+		return outer
+	}
+	out := make(map[string]any, len(innerMap)+len(outerMap))
+	for k, v := range innerMap {
+		out[k] = v
+	}
+	for k, v := range outerMap {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,260 @@
+package berror
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const depthExceededMarker = "...(max depth exceeded)"
+
+var (
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	objectMarshalerType = reflect.TypeOf((*zapcore.ObjectMarshaler)(nil)).Elem()
+)
+
+// limitDetailDepth returns the detail unchanged if its nesting depth is within maxDetailDepth,
+// otherwise returns a generic copy(maps, slices and basic values) cut off with a marker beyond the depth.
+// a reference back to an enclosing value is cut off with the marker as well,
+// and at most maxDetailNodes values are visited if it is set.
+// the values within the limits are kept as they are, so their own JSON encoding is not changed.
+// it protects the serialization from deeply nested or self-referential details.
+func limitDetailDepth(detail any) any {
+	if maxDetailDepth <= 0 || detail == nil {
+		return detail
+	}
+	v := reflect.ValueOf(detail)
+	if !newDepthGuard().exceeds(v, 0) {
+		return detail
+	}
+	return newDepthGuard().cut(v, 0)
+}
+
+// refKey identifies a referenced value(pointer, map or slice)
+type refKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// depthGuard walks the detail tracking the references on the current path and the visited nodes.
+// only the containers(struct, map, slice and array) count as a level of depth,
+// pointers and interfaces do not.
+type depthGuard struct {
+	path  map[refKey]struct{}
+	nodes int
+}
+
+func newDepthGuard() *depthGuard {
+	return &depthGuard{path: make(map[refKey]struct{})}
+}
+
+// enter mark the reference of v on the current path,
+// returns false if v refers back to an enclosing value.
+// the returned func removes the mark.
+func (g *depthGuard) enter(v reflect.Value) (func(), bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+	default:
+		return func() {}, true
+	}
+	if v.IsNil() || (v.Kind() == reflect.Slice && v.Len() == 0) {
+		return func() {}, true
+	}
+	key := refKey{ptr: v.Pointer(), typ: v.Type()}
+	if _, exist := g.path[key]; exist {
+		return nil, false
+	}
+	g.path[key] = struct{}{}
+	return func() { delete(g.path, key) }, true
+}
+
+// isLeaf determine whether the value is serialized without recursion into it.
+func isLeaf(v reflect.Value) bool {
+	t := v.Type()
+	if t.Implements(jsonMarshalerType) || t.Implements(objectMarshalerType) {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Struct, reflect.Map:
+		return false
+	case reflect.Slice, reflect.Array:
+		// a slice of basic values, e.g. []byte or []int, has no nesting
+		switch t.Elem().Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+			return false
+		}
+	}
+	return true
+}
+
+// overflow determine whether the node at the depth exceeds the limits.
+func (g *depthGuard) overflow(depth int) bool {
+	g.nodes++
+	return depth > maxDetailDepth || (maxDetailNodes > 0 && g.nodes > maxDetailNodes)
+}
+
+func (g *depthGuard) exceeds(v reflect.Value, depth int) bool {
+	if g.overflow(depth) {
+		return true
+	}
+	if !v.IsValid() || isLeaf(v) {
+		return false
+	}
+	leave, ok := g.enter(v)
+	if !ok {
+		return true
+	}
+	defer leave()
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return !v.IsNil() && g.exceeds(v.Elem(), depth)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			switch {
+			case field.Anonymous:
+				// the fields of an embedded struct are promoted to the current level
+				if g.exceeds(v.Field(i), depth) {
+					return true
+				}
+			case field.IsExported():
+				if g.exceeds(v.Field(i), depth+1) {
+					return true
+				}
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if g.exceeds(iter.Value(), depth+1) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if g.exceeds(v.Index(i), depth+1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (g *depthGuard) cut(v reflect.Value, depth int) any {
+	if g.overflow(depth) {
+		return depthExceededMarker
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	if isLeaf(v) {
+		return v.Interface()
+	}
+	leave, ok := g.enter(v)
+	if !ok {
+		return depthExceededMarker
+	}
+	defer leave()
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return g.cut(v.Elem(), depth)
+	case reflect.Struct:
+		// keep the struct as it is if it is within the limits, respecting its json tags
+		sub := &depthGuard{path: g.path, nodes: g.nodes}
+		if !sub.exceeds(v, depth) {
+			return v.Interface()
+		}
+		out := make(map[string]any, v.NumField())
+		g.cutStruct(v, depth, out)
+		return out
+	case reflect.Map:
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = g.cut(iter.Value(), depth+1)
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = g.cut(v.Index(i), depth+1)
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+// cutStruct copy the exported fields of the struct into out following the rules of encoding/json:
+// "-" fields are skipped, "omitempty" fields with an empty value are skipped,
+// and the fields of an untagged embedded struct are promoted unless shadowed by the outer ones.
+func (g *depthGuard) cutStruct(v reflect.Value, depth int, out map[string]any) {
+	var embedded []reflect.Value
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			fv := v.Field(i)
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() || !field.IsExported() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				embedded = append(embedded, fv)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(","+opts+",", ",omitempty,") && isEmptyValue(v.Field(i)) {
+			continue
+		}
+		out[name] = g.cut(v.Field(i), depth+1)
+	}
+	for _, fv := range embedded {
+		inner := make(map[string]any, fv.NumField())
+		g.cutStruct(fv, depth, inner)
+		for k, val := range inner {
+			if _, exist := out[k]; !exist {
+				out[k] = val
+			}
+		}
+	}
+}
+
+// isEmptyValue the "empty" value of the omitempty option of encoding/json
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
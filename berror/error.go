@@ -1,23 +1,46 @@
 package berror
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"reflect"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/lamber92/go-brick/berror/bcode"
 	"github.com/lamber92/go-brick/berror/bstatus"
 	"github.com/lamber92/go-brick/bstack"
+	"github.com/lamber92/go-brick/btrace"
 	"go.uber.org/zap/zapcore"
 )
 
 var jsonStdIter = jsoniter.ConfigCompatibleWithStandardLibrary
 
+// jsonFastIter the same as jsonStdIter but without sorting map keys
+var jsonFastIter = jsoniter.Config{
+	EscapeHTML:             true,
+	ValidateJsonRawMessage: true,
+}.Froze()
+
 // defaultError
 // Provide built-in error status carrier
 type defaultError struct {
 	err    error            // original error
 	status bstatus.Status   // business information
 	stack  bstack.StackList // stack information when this object(*defaultError) was created
+	// trace id used to correlate the error with traces
+	traceID string
+	// documentation url of the error
+	helpURL string
+	// low-cardinality labels used as metrics dimensions
+	labels map[string]string
+	// the time when this object(*defaultError) was created
+	createdAt time.Time
+	// additional structured detail objects, e.g. gRPC error_details
+	details []any
+	// whether the error is a sentinel matched by code in errors.Is
+	sentinel bool
 }
 
 // New create and return an error containing a code and reason.
@@ -29,45 +52,152 @@ type defaultError struct {
 // nb 2. if @err type is *defaultError,
 // the @err stack will be inherited.
 func New(status bstatus.Status, err ...error) Error {
-	e := &defaultError{status: status}
-	// check original err and try to inherit err-stack
+	var orig error
 	if len(err) > 0 {
-		e.err = err[0]
-		if orig, ok := e.err.(*defaultError); ok {
-			e.stack = orig.stack
-		}
+		orig = err[0]
 	}
-	// generate new stack info
-	if e.stack == nil {
-		e.stack = bstack.TakeStack(1, bstack.StacktraceMax)
+	return newError(orig, status, 1)
+}
+
+// NewCtx create and return an error like New,
+// and populate the trace id from the context.
+func NewCtx(ctx context.Context, status bstatus.Status, err ...error) Error {
+	var orig error
+	if len(err) > 0 {
+		orig = err[0]
+	}
+	e := newError(orig, status, 1)
+	if ctx != nil {
+		if traceID := btrace.GetTraceID(ctx); traceID != "" {
+			e.traceID = traceID
+		}
 	}
 	return e
 }
 
+// maxStackSkip the max number of stack frames NewWithSkip is allowed to skip
+const maxStackSkip = 32
+
 // NewWithSkip create and return an error containing the stack trace.
-// @offset: offset stack depth
+// @skip: offset stack depth, 0 identifies the caller of NewWithSkip.
+// it is clamped to [0, maxStackSkip], and if it still skips the whole stack,
+// the stack is captured from the caller of NewWithSkip instead.
 //
 // nb. if @err type is *defaultError,
 // the @err stack will be inherited.
 func NewWithSkip(err error, status bstatus.Status, skip int) Error {
+	if skip < 0 {
+		skip = 0
+	} else if skip > maxStackSkip {
+		skip = maxStackSkip
+	}
+	return newError(err, status, skip+1)
+}
+
+// newError create an error wrapping @err.
+// @skip: the number of stack frames to skip above the caller of newError
+func newError(err error, status bstatus.Status, skip int) *defaultError {
+	// a typed-nil error(e.g. (*MyErr)(nil)) is treated as no cause
+	if isNilError(err) {
+		err = nil
+	}
 	e := &defaultError{
-		err:    err,
-		status: status,
+		err:       err,
+		status:    status,
+		createdAt: clock(),
 	}
 	// check original err and try to inherit err-stack
-	if err != nil {
-		e.err = err
-		if orig, ok := e.err.(*defaultError); ok {
-			e.stack = orig.stack
+	if orig, ok := err.(*defaultError); ok {
+		if collapseSameCode && sameCodeAndReason(orig.status, status) {
+			// keep only one level and merge the detail,
+			// the inner level's labels, help url and detail objects are kept as well
+			e.err = orig.err
+			e.status = bstatus.WithDetail(collapsedStatus(orig.status, status), mergeDetail(orig.status.Detail(), status.Detail()))
+			e.details = orig.details
+			e.labels = orig.labels
+			e.helpURL = orig.helpURL
 		}
+		e.stack = orig.stack
+		e.traceID = orig.traceID
 	}
 	// generate new stack info
-	if e.stack == nil {
+	if len(e.stack) == 0 && shouldCaptureStack(status) {
 		e.stack = bstack.TakeStack(skip+1, bstack.StacktraceMax)
+		if len(e.stack) == 0 {
+			// skipped too many frames, fall back to the caller of the exported constructor
+			e.stack = bstack.TakeStack(2, bstack.StacktraceMax)
+		}
+	}
+	if status != nil {
+		countCode(status.Code())
 	}
 	return e
 }
 
+// shouldCaptureStack determine whether the level of the status code reaches the stack capture threshold.
+func shouldCaptureStack(status bstatus.Status) bool {
+	if stackCaptureThreshold <= zapcore.DebugLevel {
+		return true
+	}
+	if status == nil {
+		return true
+	}
+	return codeLogLevel(status.Code()) >= stackCaptureThreshold
+}
+
+// codeLogLevel convert the level of the error code to the log level.
+func codeLogLevel(code bcode.Code) zapcore.Level {
+	switch bcode.GetLevel(code) {
+	case bcode.LvInfo, bcode.LvNotice:
+		return zapcore.InfoLevel
+	case bcode.LvWarning:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// isNilError determine whether err is nil or an interface wrapping a nil value.
+func isNilError(err error) bool {
+	if err == nil {
+		return true
+	}
+	v := reflect.ValueOf(err)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// collapsedStatus choose the status type kept by the collapsed level,
+// the outer one unless only the inner one carries a marker(retryable, warning or template).
+func collapsedStatus(inner, outer bstatus.Status) bstatus.Status {
+	if !hasStatusMarker(outer) && hasStatusMarker(inner) {
+		return inner
+	}
+	return outer
+}
+
+// hasStatusMarker determine whether the status is more than a plain code/reason/detail carrier.
+func hasStatusMarker(status bstatus.Status) bool {
+	if _, marked := bstatus.IsMarkedRetryable(status); marked {
+		return true
+	}
+	if _, ok := status.(bstatus.TemplateStatus); ok {
+		return true
+	}
+	return bstatus.IsWarning(status)
+}
+
+// sameCodeAndReason determine whether the two statuses have the same code and reason.
+func sameCodeAndReason(a, b bstatus.Status) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.Code().ToInt() == b.Code().ToInt() && a.Reason() == b.Reason()
+}
+
 // Error output error information in string format
 func (d *defaultError) Error() string {
 	if d == nil {
@@ -77,6 +207,15 @@ func (d *defaultError) Error() string {
 	return str
 }
 
+// MarshalJSON json.Marshaler impl, output the same structure as Error().
+// a nil receiver is marshaled to null.
+func (d *defaultError) MarshalJSON() ([]byte, error) {
+	if d == nil {
+		return []byte("null"), nil
+	}
+	return jsonStdIter.Marshal(d.format())
+}
+
 // Status get main status
 func (d *defaultError) Status() bstatus.Status {
 	if d == nil {
@@ -93,6 +232,137 @@ func (d *defaultError) Stack() bstack.StackList {
 	return d.stack
 }
 
+// clone returns a shallow copy of the error for the With* methods,
+// the copy of a sentinel is an ordinary error and is not matched by code in errors.Is.
+func (d *defaultError) clone() *defaultError {
+	cp := *d
+	cp.sentinel = false
+	return &cp
+}
+
+// WithStatus returns a copy of the error with the status replaced,
+// unlike wrapping, no new level is added.
+func (d *defaultError) WithStatus(status bstatus.Status) Error {
+	if d == nil {
+		return nil
+	}
+	cp := d.clone()
+	cp.status = status
+	return cp
+}
+
+// Time get the time when the error was created
+func (d *defaultError) Time() time.Time {
+	if d == nil {
+		return time.Time{}
+	}
+	return d.createdAt
+}
+
+// TraceID get the trace id used to correlate the error with traces
+func (d *defaultError) TraceID() string {
+	if d == nil {
+		return ""
+	}
+	return d.traceID
+}
+
+// WithTraceID returns a copy of the error with the trace id
+func (d *defaultError) WithTraceID(traceID string) Error {
+	if d == nil {
+		return nil
+	}
+	cp := d.clone()
+	cp.traceID = traceID
+	return cp
+}
+
+// HelpURL get the documentation url of the error,
+// falls back to the url registered for the error code
+func (d *defaultError) HelpURL() string {
+	if d == nil {
+		return ""
+	}
+	if d.helpURL != "" {
+		return d.helpURL
+	}
+	if d.status == nil {
+		return ""
+	}
+	return GetHelpURL(d.status.Code())
+}
+
+// WithHelpURL returns a copy of the error with the documentation url
+func (d *defaultError) WithHelpURL(url string) Error {
+	if d == nil {
+		return nil
+	}
+	cp := d.clone()
+	cp.helpURL = url
+	return cp
+}
+
+// WithLabels returns a copy of the error with the labels merged in,
+// the labels are kept separate from detail and are not serialized.
+func (d *defaultError) WithLabels(labels map[string]string) Error {
+	if d == nil {
+		return nil
+	}
+	cp := d.clone()
+	cp.labels = make(map[string]string, len(d.labels)+len(labels))
+	for k, v := range d.labels {
+		cp.labels[k] = v
+	}
+	for k, v := range labels {
+		cp.labels[k] = v
+	}
+	return cp
+}
+
+// AddDetailObject returns a copy of the error with the detail object appended,
+// the objects are rendered as the "details" array alongside the single detail.
+func (d *defaultError) AddDetailObject(detail any) Error {
+	if d == nil {
+		return nil
+	}
+	cp := d.clone()
+	cp.details = make([]any, 0, len(d.details)+1)
+	cp.details = append(cp.details, d.details...)
+	cp.details = append(cp.details, detail)
+	return cp
+}
+
+// OriginalMessage returns the message of the innermost non-brick error in the chain,
+// e.g. the driver message of a wrapped sql error. empty if none.
+func (d *defaultError) OriginalMessage() string {
+	if d == nil {
+		return ""
+	}
+	var msg string
+	err := d.err
+	for depth := 0; err != nil && depth < maxChainDepth; depth++ {
+		if _, ok := err.(*joinedErrors); ok {
+			break
+		}
+		if _, ok := err.(*defaultError); !ok {
+			msg = err.Error()
+		}
+		err = errors.Unwrap(err)
+	}
+	return msg
+}
+
+// ToPublicDTO returns the client-safe representation of the error
+func (d *defaultError) ToPublicDTO() PublicError {
+	status := d.Status()
+	return PublicError{
+		Code:    status.Code().ToInt(),
+		Reason:  status.Reason(),
+		TraceID: d.TraceID(),
+		Help:    d.HelpURL(),
+	}
+}
+
 // Cause returns the underlying cause of the error, if possible.
 func (d *defaultError) Cause() error {
 	if d == nil {
@@ -101,6 +371,30 @@ func (d *defaultError) Cause() error {
 	return d.err
 }
 
+// Causes returns all immediate child errors.
+// if the wrapped error aggregates multiple errors(implements Unwrap() []error),
+// its children are returned.
+func (d *defaultError) Causes() []error {
+	if d == nil || d.err == nil {
+		return nil
+	}
+	if multi, ok := d.err.(interface{ Unwrap() []error }); ok {
+		return multi.Unwrap()
+	}
+	return []error{d.err}
+}
+
+// Errors returns the aggregated errors if the error is created by Join, otherwise nil.
+func (d *defaultError) Errors() []error {
+	if d == nil {
+		return nil
+	}
+	if j, ok := d.err.(*joinedErrors); ok {
+		return j.Unwrap()
+	}
+	return nil
+}
+
 // Unwrap provides compatibility for Go 1.13 error chains.
 func (d *defaultError) Unwrap() error {
 	if d == nil {
@@ -110,27 +404,56 @@ func (d *defaultError) Unwrap() error {
 }
 
 type summary struct {
-	Code   bcode.Code `json:"code"`
-	Reason string     `json:"reason"`
-	Detail any        `json:"detail"`
-	Next   any        `json:"next"`
+	Code    bcode.Code `json:"code"`
+	Reason  string     `json:"reason"`
+	TraceID string     `json:"trace_id,omitempty"`
+	Help    string     `json:"help,omitempty"`
+	Origin  string     `json:"origin,omitempty"`
+	Detail  any        `json:"detail"`
+	Details []any      `json:"details,omitempty"`
+	Next    any        `json:"next"`
 }
 
-func (d *defaultError) format() *summary {
+func (d *defaultError) format() any {
+	if d == nil {
+		return nil
+	}
+	return d.summarize(visitedSet{})
+}
+
+// summarize build the summary of the current level and the nested levels.
+// @visited: the levels on the path to the current one, the current level is the outermost one if empty
+func (d *defaultError) summarize(visited visitedSet) *summary {
 	if d == nil || d.status == nil {
 		return nil
 	}
+	top := len(visited) == 0
+	visited[d] = struct{}{}
+	defer delete(visited, d)
 	sum := &summary{
-		Code:   d.status.Code(),
-		Reason: d.status.Reason(),
-		Detail: d.status.Detail(),
+		Code:    d.status.Code(),
+		Reason:  d.status.Reason(),
+		Help:    d.HelpURL(),
+		Detail:  formatDetail(d.detail(top)),
+		Details: formatDetails(d.details),
+	}
+	// the trace id is inherited by the wrapping levels, only render it once
+	if top {
+		sum.TraceID = d.traceID
+		if originOutput {
+			sum.Origin = d.Stack().Origin()
+		}
 	}
 	if d.err == nil {
 		sum.Next = nil
+	} else if visited.reaches(d.err) {
+		sum.Next = cycleMarker
 	} else {
 		switch next := d.err.(type) {
 		case *defaultError:
-			sum.Next = next.format()
+			sum.Next = next.summarize(visited)
+		case *joinedErrors:
+			sum.Next = next.summarize(visited)
 		default:
 			sum.Next = next.Error()
 		}
@@ -138,26 +461,67 @@ func (d *defaultError) format() *summary {
 	return sum
 }
 
+// detail returns the detail of the current level used for serialization.
+// the global detail is only merged into the outermost level.
+func (d *defaultError) detail(top bool) any {
+	if top {
+		return mergeGlobalDetail(d.status.Detail())
+	}
+	return d.status.Detail()
+}
+
 // MarshalLogObject zapcore.ObjectMarshaler impl
-func (d *defaultError) MarshalLogObject(enc zapcore.ObjectEncoder) (err error) {
+func (d *defaultError) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return d.marshalLogObject(enc, visitedSet{})
+}
+
+func (d *defaultError) marshalLogObject(enc zapcore.ObjectEncoder, visited visitedSet) (err error) {
+	top := len(visited) == 0
+	visited[d] = struct{}{}
+	defer delete(visited, d)
 	// code/reason
 	status := d.status
 	enc.AddInt("code", status.Code().ToInt())
 	enc.AddString("reason", status.Reason())
-	// detail
-	if status.Detail() != nil {
-		if obj, ok := status.Detail().(zapcore.ObjectMarshaler); ok {
-			_ = enc.AddObject("detail", obj)
-		} else {
-			_ = enc.AddReflected("detail", status.Detail())
+	// trace id
+	if top && d.traceID != "" {
+		enc.AddString("trace_id", d.traceID)
+	}
+	// help url
+	if help := d.HelpURL(); help != "" {
+		enc.AddString("help", help)
+	}
+	// origin
+	if top && originOutput {
+		if origin := d.Stack().Origin(); origin != "" {
+			enc.AddString("origin", origin)
 		}
 	}
+	// detail
+	if detail := d.detail(top); detail != nil {
+		addDetail(enc, "detail", detail)
+	}
+	if len(d.details) > 0 {
+		addDetails(enc, "details", d.details)
+	}
 	// nest error
 	if d.err == nil {
 		return
 	}
+	if visited.reaches(d.err) {
+		enc.AddString("next", cycleMarker)
+		return
+	}
 	if next, ok := d.err.(*defaultError); ok {
-		_ = enc.AddObject("next", next)
+		_ = enc.AddObject("next", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			return next.marshalLogObject(enc, visited)
+		}))
+		return
+	}
+	if next, ok := d.err.(*joinedErrors); ok {
+		_ = enc.AddArray("next", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+			return next.marshalLogArray(enc, visited)
+		}))
 		return
 	}
 	enc.AddString("next", d.err.Error())
@@ -227,6 +591,69 @@ func NewInternalError(err error, reason string, detail ...any) error {
 	return NewWithSkip(err, bstatus.New(bcode.InternalError, reason, ds), 1)
 }
 
+// WithDetail returns a new error with the same code, reason, stack and cause as @err,
+// but the detail replaced by @detail.
+// returns nil if @err is nil, and @err itself if @detail is nil.
+func WithDetail(err Error, detail any) Error {
+	if isNilError(err) {
+		return nil
+	}
+	if detail == nil {
+		return err
+	}
+	status := err.Status()
+	if status == nil {
+		status = bstatus.Unknown
+	}
+	newStatus := bstatus.WithDetail(status, detail)
+	if d, ok := err.(*defaultError); ok {
+		cp := d.clone()
+		cp.status = newStatus
+		return cp
+	}
+	return &defaultError{
+		err:     err.Cause(),
+		status:  newStatus,
+		stack:   err.Stack(),
+		traceID: err.TraceID(),
+		helpURL: err.HelpURL(),
+	}
+}
+
+// Newf create an error of the code with a formatted reason,
+// mainly for custom registered codes that don't have a dedicated helper.
+func Newf(code bcode.Code, err error, format string, args ...any) Error {
+	return NewWithSkip(err, bstatus.New(code, fmt.Sprintf(format, args...), nil), 1)
+}
+
+// WrapMessage wrap err with a new reason, keeping the code of the wrapped brick error,
+// the reason is replaced by @message and the inner reason stays in the chain.
+// if err is not a brick error, the code defaults to InternalError.
+// returns nil if err is nil.
+func WrapMessage(err error, message string) Error {
+	if isNilError(err) {
+		return nil
+	}
+	var code bcode.Code = bcode.InternalError
+	if e, ok := AsError(err); ok && e.Status() != nil {
+		code = e.Status().Code()
+	}
+	return NewWithSkip(err, bstatus.New(code, message, nil), 1)
+}
+
+// AsError find the first Error in the chain of err.
+// it is a typed convenience wrapper of errors.As.
+func AsError(err error) (Error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var e Error
+	if ok := errors.As(err, &e); !ok {
+		return nil, false
+	}
+	return e, true
+}
+
 // IsCode determine whether the error code of err meets expectations.
 func IsCode(err error, code bcode.Code) bool {
 	if err == nil {
@@ -241,3 +668,35 @@ func IsCode(err error, code bcode.Code) bool {
 	}
 	return false
 }
+
+// IsCodeAny determine whether the error code of err matches any of the codes.
+// the error chain is unwrapped only once.
+func IsCodeAny(err error, codes ...bcode.Code) bool {
+	if err == nil || len(codes) == 0 {
+		return false
+	}
+	var e Error
+	if ok := errors.As(err, &e); !ok {
+		return false
+	}
+	target := e.Status().Code().ToInt()
+	for _, code := range codes {
+		if target == code.ToInt() {
+			return true
+		}
+	}
+	return false
+}
+
+// AffectsSLO determine whether the error should be counted against availability.
+// errors that are not Error or have no status are treated as unknown errors, which affect SLO.
+func AffectsSLO(err error) bool {
+	if err == nil {
+		return false
+	}
+	var e Error
+	if ok := errors.As(err, &e); !ok || e.Status() == nil {
+		return bcode.GetSLOCategory(bcode.Unknown) == bcode.SLOAffecting
+	}
+	return bcode.GetSLOCategory(e.Status().Code()) == bcode.SLOAffecting
+}
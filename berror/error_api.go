@@ -1,13 +1,17 @@
 package berror
 
 import (
+	"time"
+
 	"github.com/lamber92/go-brick/berror/bstatus"
 	"github.com/lamber92/go-brick/bstack"
 )
 
 //go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative  --proto_path=. grpc_status_detail.proto
 
-// Error Provide the interface for feeding back business error info
+// Error Provide the interface for feeding back business error info.
+// nb. the built-in implementation also implements net.Error(see Timeout/Temporary in net.go),
+// so every brick error matches errors.As(err, &netErr), whether it is related to the network or not.
 type Error interface {
 	Chain
 	// Error output error information in string format.
@@ -16,6 +20,43 @@ type Error interface {
 	Status() bstatus.Status
 	// Stack tracking list the error tracking information that has been collected.
 	Stack() bstack.StackList
+	// WithStatus returns a copy of the error with the status replaced,
+	// keeping the same inner error chain and stack.
+	WithStatus(status bstatus.Status) Error
+	// Time get the time when the error was created.
+	Time() time.Time
+	// TraceID get the trace id used to correlate the error with traces.
+	TraceID() string
+	// WithTraceID returns a copy of the error with the trace id.
+	WithTraceID(traceID string) Error
+	// HelpURL get the documentation url of the error,
+	// falls back to the url registered for the error code.
+	HelpURL() string
+	// WithHelpURL returns a copy of the error with the documentation url.
+	WithHelpURL(url string) Error
+	// WithLabels returns a copy of the error with the low-cardinality labels merged in,
+	// e.g. "operation" or "resource_type" used as metrics dimensions.
+	WithLabels(labels map[string]string) Error
+	// AddDetailObject returns a copy of the error with the detail object appended,
+	// rendered as the "details" array alongside the single detail.
+	AddDetailObject(detail any) Error
+	// OriginalMessage returns the message of the innermost non-brick error in the chain,
+	// empty if none.
+	OriginalMessage() string
+	// Errors returns the aggregated errors if the error is created by Join, otherwise nil.
+	Errors() []error
+	// ToPublicDTO returns the client-safe representation of the error,
+	// without the nested chain and the internal detail.
+	ToPublicDTO() PublicError
+}
+
+// PublicError the client-safe representation of Error,
+// suitable for direct JSON encoding in responses.
+type PublicError struct {
+	Code    int    `json:"code"`
+	Reason  string `json:"reason"`
+	TraceID string `json:"trace_id,omitempty"`
+	Help    string `json:"help,omitempty"`
 }
 
 type Chain interface {
@@ -23,4 +64,7 @@ type Chain interface {
 	Cause() error
 	// Unwrap provides compatibility for Go 1.13 error chains.
 	Unwrap() error
+	// Causes returns all immediate child errors,
+	// one element for a normal wrap, many for an aggregated error.
+	Causes() []error
 }
@@ -0,0 +1,36 @@
+package berror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsConcreteError(t *testing.T) {
+	inner := New(bstatus.NotFound, errors.New("root"))
+	outer := New(bstatus.InternalError, inner)
+	wrapped := fmt.Errorf("outer: %w", outer)
+
+	var target *defaultError
+	assert.Equal(t, true, errors.As(wrapped, &target))
+	assert.Equal(t, outer, target)
+
+	// the innermost level is reachable when the outer level is skipped
+	target = nil
+	assert.Equal(t, true, errors.As(fmt.Errorf("x: %w", outer.Cause()), &target))
+	assert.Equal(t, inner, target)
+
+	target = nil
+	assert.Equal(t, false, errors.As(errors.New("plain"), &target))
+	assert.Nil(t, target)
+}
+
+func TestNilMarshalJSON(t *testing.T) {
+	var d *defaultError
+	raw, err := d.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(raw))
+}
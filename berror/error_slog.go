@@ -0,0 +1,59 @@
+//go:build go1.21
+
+package berror
+
+import "log/slog"
+
+// LogValue slog.LogValuer impl, mirroring MarshalLogObject
+func (d *defaultError) LogValue() slog.Value {
+	if d == nil || d.status == nil {
+		return slog.Value{}
+	}
+	return d.logValue(visitedSet{})
+}
+
+func (d *defaultError) logValue(visited visitedSet) slog.Value {
+	top := len(visited) == 0
+	visited[d] = struct{}{}
+	defer delete(visited, d)
+	// code/reason
+	attrs := []slog.Attr{
+		slog.Int("code", d.status.Code().ToInt()),
+		slog.String("reason", d.status.Reason()),
+	}
+	// trace id
+	if top && d.traceID != "" {
+		attrs = append(attrs, slog.String("trace_id", d.traceID))
+	}
+	// help url
+	if help := d.HelpURL(); help != "" {
+		attrs = append(attrs, slog.String("help", help))
+	}
+	// origin
+	if top && originOutput {
+		if origin := d.Stack().Origin(); origin != "" {
+			attrs = append(attrs, slog.String("origin", origin))
+		}
+	}
+	// detail
+	if detail := d.detail(top); detail != nil {
+		attrs = append(attrs, slog.Any("detail", formatDetail(detail)))
+	}
+	if len(d.details) > 0 {
+		attrs = append(attrs, slog.Any("details", formatDetails(d.details)))
+	}
+	// nest error
+	if d.err != nil {
+		if visited.reaches(d.err) {
+			attrs = append(attrs, slog.String("next", cycleMarker))
+		} else if next, ok := d.err.(*defaultError); ok {
+			attrs = append(attrs, slog.Attr{Key: "next", Value: next.logValue(visited)})
+		} else if next, ok := d.err.(*joinedErrors); ok {
+			// a joined error is rendered as the list of its children
+			attrs = append(attrs, slog.Any("next", next.summarize(visited)))
+		} else {
+			attrs = append(attrs, slog.String("next", d.err.Error()))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
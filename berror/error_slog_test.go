@@ -0,0 +1,65 @@
+//go:build go1.21
+
+package berror_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultError_LogValue(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := slog.New(slog.NewJSONHandler(buf, nil))
+
+	err1 := berror.New(bstatus.InternalError, errors.New("root"))
+	err2 := berror.New(bstatus.New(bcode.NotFound, "not found", map[string]any{"id": 1}), err1)
+	log.Error("failed", "err", err2)
+
+	var out struct {
+		Err struct {
+			Code   int            `json:"code"`
+			Reason string         `json:"reason"`
+			Detail map[string]any `json:"detail"`
+			Next   struct {
+				Code   int    `json:"code"`
+				Reason string `json:"reason"`
+				Next   string `json:"next"`
+			} `json:"next"`
+		} `json:"err"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &out), buf.String())
+	assert.Equal(t, bcode.NotFound.ToInt(), out.Err.Code)
+	assert.Equal(t, "not found", out.Err.Reason)
+	assert.Equal(t, map[string]any{"id": float64(1)}, out.Err.Detail)
+	assert.Equal(t, bcode.InternalError.ToInt(), out.Err.Next.Code)
+	assert.Equal(t, bstatus.InternalError.Reason(), out.Err.Next.Reason)
+	assert.Equal(t, "root", out.Err.Next.Next)
+}
+
+func TestDefaultError_LogValueJoined(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := slog.New(slog.NewJSONHandler(buf, nil))
+
+	err := berror.Join(berror.New(bstatus.NotFound), errors.New("plain"))
+	log.Error("failed", "err", err)
+
+	var out struct {
+		Err struct {
+			Code int   `json:"code"`
+			Next []any `json:"next"`
+		} `json:"err"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &out), buf.String())
+	assert.Equal(t, bcode.NotFound.ToInt(), out.Err.Code)
+	assert.Len(t, out.Err.Next, 2)
+	assert.Equal(t, float64(bcode.NotFound.ToInt()), out.Err.Next[0].(map[string]any)["code"])
+	assert.Equal(t, "plain", out.Err.Next[1])
+}
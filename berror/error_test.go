@@ -1,15 +1,22 @@
 package berror_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/lamber92/go-brick/bcontext"
 	"github.com/lamber92/go-brick/berror"
 	"github.com/lamber92/go-brick/berror/bcode"
 	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/lamber92/go-brick/btrace"
 	xerrors "github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
 )
 
 const (
@@ -92,6 +99,16 @@ func TestIsCode(t *testing.T) {
 	assert.Equal(t, false, berror.IsCode(err4, bcode.Forbidden))
 }
 
+func TestIsCodeAny(t *testing.T) {
+	_, _, _, err4 := generateTestError()
+	assert.Equal(t, true, berror.IsCodeAny(err4, bcode.NotFound, bcode.AlreadyExists))
+	assert.Equal(t, true, berror.IsCodeAny(err4, bcode.AlreadyExists, bcode.NotFound))
+	assert.Equal(t, false, berror.IsCodeAny(err4, bcode.Forbidden, bcode.InternalError))
+	assert.Equal(t, false, berror.IsCodeAny(err4))
+	assert.Equal(t, false, berror.IsCodeAny(nil, bcode.NotFound))
+	assert.Equal(t, false, berror.IsCodeAny(errors.New("plain"), bcode.NotFound))
+}
+
 func TestTypeSwitch(t *testing.T) {
 	err := berror.NewInternalError(nil, "some error")
 	switch e := err.(type) {
@@ -111,3 +128,577 @@ func TestTypeSwitch(t *testing.T) {
 	}
 	// berror.Chain {"code":500,"reason":"some error 2","detail":null,"next":null}
 }
+
+func TestAffectsSLO(t *testing.T) {
+	assert.Equal(t, false, berror.AffectsSLO(nil))
+	assert.Equal(t, false, berror.AffectsSLO(berror.NewInvalidArgument(nil, "bad param")))
+	assert.Equal(t, false, berror.AffectsSLO(berror.NewNotFound(nil, "not found")))
+	assert.Equal(t, true, berror.AffectsSLO(berror.NewInternalError(nil, "internal")))
+	assert.Equal(t, true, berror.AffectsSLO(berror.NewGatewayTimeout(nil, "timeout")))
+	assert.Equal(t, true, berror.AffectsSLO(errors.New("plain error")))
+	assert.Equal(t, true, berror.AffectsSLO(berror.New(nil)))
+
+	_, _, _, err4 := generateTestError()
+	assert.Equal(t, false, berror.AffectsSLO(err4))
+}
+
+func TestDetailTruncation(t *testing.T) {
+	berror.SetMaxDetailSize(64)
+	defer berror.SetMaxDetailSize(0)
+
+	detail := make([]int, 1000)
+	err := berror.New(bstatus.New(bcode.InternalError, "large detail", detail))
+
+	assert.Contains(t, err.Error(), "...(truncated)")
+	assert.Less(t, len(err.Error()), 256)
+
+	enc := zapcore.NewMapObjectEncoder()
+	assert.NoError(t, err.(zapcore.ObjectMarshaler).MarshalLogObject(enc))
+	assert.Contains(t, enc.Fields["detail"], "...(truncated)")
+
+	// the original error keeps the full detail
+	assert.Equal(t, detail, err.Status().Detail())
+
+	// small details are not affected
+	err2 := berror.New(bstatus.New(bcode.InternalError, "small detail", "abc"))
+	assert.NotContains(t, err2.Error(), "...(truncated)")
+}
+
+func TestGlobalDetail(t *testing.T) {
+	berror.SetGlobalDetail(map[string]any{"service": "brick", "version": "v1.0.0"})
+	defer berror.SetGlobalDetail(nil)
+
+	err1 := berror.New(bstatus.New(bcode.InternalError, "no detail", nil))
+	assert.Contains(t, err1.Error(), `"detail":{"service":"brick","version":"v1.0.0"}`)
+
+	err2 := berror.New(bstatus.New(bcode.InternalError, "override", map[string]any{"version": "v2.0.0"}))
+	assert.Contains(t, err2.Error(), `"version":"v2.0.0"`)
+	assert.NotContains(t, err2.Error(), `"version":"v1.0.0"`)
+	assert.Contains(t, err2.Error(), `"service":"brick"`)
+
+	enc := zapcore.NewMapObjectEncoder()
+	assert.NoError(t, err2.(zapcore.ObjectMarshaler).MarshalLogObject(enc))
+	assert.Equal(t, map[string]any{"service": "brick", "version": "v2.0.0"}, enc.Fields["detail"])
+
+	// the error keeps its own detail
+	assert.Equal(t, map[string]any{"version": "v2.0.0"}, err2.Status().Detail())
+}
+
+func TestCollapseSameCode(t *testing.T) {
+	berror.SetCollapseSameCode(true)
+	defer berror.SetCollapseSameCode(false)
+
+	root := errors.New("root")
+	err1 := berror.New(bstatus.New(bcode.InternalError, "failed", map[string]any{"a": 1}), root)
+	err2 := berror.New(bstatus.New(bcode.InternalError, "failed", map[string]any{"b": 2}), err1)
+
+	// collapsed into one level
+	assert.Equal(t, root, err2.Cause())
+	assert.Equal(t, map[string]any{"a": 1, "b": 2}, err2.Status().Detail())
+	assert.Equal(t, err1.Stack(), err2.Stack())
+
+	// distinct codes are preserved
+	err3 := berror.New(bstatus.New(bcode.NotFound, "failed", nil), err1)
+	assert.Equal(t, err1, err3.Cause())
+
+	// distinct reasons are preserved
+	err4 := berror.New(bstatus.New(bcode.InternalError, "other", nil), err1)
+	assert.Equal(t, err1, err4.Cause())
+
+	// status markers, labels and help url of the inner level are kept
+	inner := berror.New(bstatus.NewRetryable(bcode.InternalError, "failed", nil), root).
+		WithLabels(map[string]string{"operation": "save"}).
+		WithHelpURL("https://example.com/help")
+	outer := berror.New(bstatus.New(bcode.InternalError, "failed", map[string]any{"b": 2}), inner)
+	assert.Equal(t, root, outer.Cause())
+	assert.Equal(t, true, berror.Retryable(outer))
+	assert.Equal(t, map[string]string{"operation": "save"}, berror.Labels(outer))
+	assert.Equal(t, "https://example.com/help", outer.HelpURL())
+	assert.Equal(t, map[string]any{"b": 2}, outer.Status().Detail())
+}
+
+type cyclicWrapper struct {
+	err error
+}
+
+func (w *cyclicWrapper) Error() string { return "wrapper: " + w.err.Error() }
+
+func (w *cyclicWrapper) Unwrap() error { return w.err }
+
+func TestCyclicChain(t *testing.T) {
+	w := &cyclicWrapper{}
+	err := berror.New(bstatus.InternalError, w)
+	w.err = berror.New(bstatus.NotFound, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var out map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(err.Error()), &out))
+		assert.Equal(t, "<cycle>", out["next"])
+
+		enc := zapcore.NewMapObjectEncoder()
+		assert.NoError(t, err.(zapcore.ObjectMarshaler).MarshalLogObject(enc))
+		assert.Equal(t, "<cycle>", enc.Fields["next"])
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("traversal of the cyclic chain does not terminate")
+	}
+}
+
+func TestTraceID(t *testing.T) {
+	err1 := berror.NewNotFound(nil, "not found").(berror.Error)
+	assert.NotContains(t, err1.Error(), "trace_id")
+
+	err2 := err1.WithTraceID("trace-1")
+	assert.Equal(t, "trace-1", err2.TraceID())
+	assert.Equal(t, "", err1.TraceID())
+	assert.Contains(t, err2.Error(), `"trace_id":"trace-1"`)
+
+	enc := zapcore.NewMapObjectEncoder()
+	assert.NoError(t, err2.(zapcore.ObjectMarshaler).MarshalLogObject(enc))
+	assert.Equal(t, "trace-1", enc.Fields["trace_id"])
+
+	// inherited when wrapped
+	err3 := berror.New(bstatus.InternalError, err2)
+	assert.Equal(t, "trace-1", err3.TraceID())
+
+	// populated from the context
+	ctx := btrace.SetTraceID(bcontext.New(), "trace-2")
+	err4 := berror.NewCtx(ctx, bstatus.InternalError)
+	assert.Equal(t, "trace-2", err4.TraceID())
+	assert.Contains(t, err4.Error(), `"trace_id":"trace-2"`)
+}
+
+func TestHelpURL(t *testing.T) {
+	err1 := berror.NewInvalidArgument(nil, "bad param").(berror.Error)
+	assert.Equal(t, "", err1.HelpURL())
+	assert.NotContains(t, err1.Error(), `"help"`)
+
+	err2 := err1.WithHelpURL("https://docs.example.com/errors/bad-param")
+	assert.Equal(t, "https://docs.example.com/errors/bad-param", err2.HelpURL())
+	assert.Contains(t, err2.Error(), `"help":"https://docs.example.com/errors/bad-param"`)
+
+	// per-code default
+	code := bcode.New(77777)
+	berror.RegisterHelpURL(code, "https://docs.example.com/errors/77777")
+	err3 := berror.New(bstatus.New(code, "custom", nil))
+	assert.Equal(t, "https://docs.example.com/errors/77777", err3.HelpURL())
+	assert.Contains(t, err3.Error(), `"help":"https://docs.example.com/errors/77777"`)
+
+	enc := zapcore.NewMapObjectEncoder()
+	assert.NoError(t, err3.(zapcore.ObjectMarshaler).MarshalLogObject(enc))
+	assert.Equal(t, "https://docs.example.com/errors/77777", enc.Fields["help"])
+
+	// own url overrides the default
+	err4 := err3.WithHelpURL("https://docs.example.com/override")
+	assert.Equal(t, "https://docs.example.com/override", err4.HelpURL())
+}
+
+func TestNewWithSkipBounds(t *testing.T) {
+	const caller = "github.com/lamber92/go-brick/berror_test.TestNewWithSkipBounds"
+
+	err1 := berror.NewWithSkip(nil, bstatus.InternalError, -5)
+	stack1 := err1.Stack()
+	assert.NotEmpty(t, stack1)
+	assert.Contains(t, stack1.Error(), `"func":"`+caller+`"`)
+	assert.NotContains(t, stack1.Error(), "berror.NewWithSkip")
+
+	err2 := berror.NewWithSkip(nil, bstatus.InternalError, 1<<20)
+	stack2 := err2.Stack()
+	assert.NotEmpty(t, stack2)
+	assert.Contains(t, stack2.Error(), `"func":"`+caller+`"`)
+}
+
+func TestNewf(t *testing.T) {
+	code := bcode.New(66666)
+	root := errors.New("root")
+	err := berror.Newf(code, root, "order %d of user %s is locked", 42, "lamber")
+
+	assert.Equal(t, true, berror.IsCode(err, code))
+	assert.Equal(t, "order 42 of user lamber is locked", err.Status().Reason())
+	assert.Equal(t, root, err.Cause())
+	assert.Contains(t, err.Stack().Error(), `"func":"github.com/lamber92/go-brick/berror_test.TestNewf"`)
+	assert.NotContains(t, err.Stack().Error(), "berror.Newf")
+}
+
+type typedNilError struct {
+	msg string
+}
+
+func (e *typedNilError) Error() string { return e.msg }
+
+func TestTypedNilCause(t *testing.T) {
+	var cause *typedNilError
+	err1 := berror.New(bstatus.InternalError, cause)
+	assert.Nil(t, err1.Cause())
+	assert.Nil(t, err1.Unwrap())
+	assert.NotPanics(t, func() { _ = err1.Error() })
+	assert.Contains(t, err1.Error(), `"next":null`)
+
+	var cause2 *typedNilError
+	err2 := berror.NewInternalError(cause2, "typed nil")
+	assert.Nil(t, err2.(berror.Error).Cause())
+	assert.NotPanics(t, func() { _ = err2.Error() })
+}
+
+type joinedError struct {
+	errs []error
+}
+
+func (e *joinedError) Error() string   { return "joined" }
+func (e *joinedError) Unwrap() []error { return e.errs }
+
+func TestDefaultError_Causes(t *testing.T) {
+	err1, err2, err3, err4 := generateTestError()
+	assert.Equal(t, []error{err3}, err4.(berror.Error).Causes())
+	assert.Equal(t, []error{err2}, err3.(berror.Error).Causes())
+
+	joined := &joinedError{errs: []error{err1, err3}}
+	err5 := berror.New(bstatus.InternalError, joined)
+	assert.Equal(t, []error{err1, err3}, err5.Causes())
+
+	err6 := berror.New(bstatus.InternalError)
+	assert.Empty(t, err6.Causes())
+}
+
+func TestDefaultError_ToPublicDTO(t *testing.T) {
+	_, _, _, err4 := generateTestError()
+	e := err4.(berror.Error).WithTraceID("trace-1").WithHelpURL("https://docs.example.com/not-found")
+
+	dto := e.ToPublicDTO()
+	assert.Equal(t, berror.PublicError{
+		Code:    bcode.NotFound.ToInt(),
+		Reason:  testErr4reason,
+		TraceID: "trace-1",
+		Help:    "https://docs.example.com/not-found",
+	}, dto)
+
+	raw, err := json.Marshal(dto)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"code":404,"reason":"yyyy","trace_id":"trace-1","help":"https://docs.example.com/not-found"}`, string(raw))
+	assert.NotContains(t, string(raw), "next")
+	assert.NotContains(t, string(raw), testErr4detail)
+}
+
+type customJSONDetail struct {
+	ID   int
+	Name string
+}
+
+func (c customJSONDetail) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{"user_id": c.ID, "user_name": c.Name})
+}
+
+func TestDetailJSONMarshaler(t *testing.T) {
+	// a large integer id is not representable as float64
+	const id = 1<<60 + 1
+	err := berror.New(bstatus.New(bcode.NotFound, "not found", customJSONDetail{ID: id, Name: "lamber"}))
+
+	decode := func(raw []byte, v any) error {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		return dec.Decode(v)
+	}
+	var fromError struct {
+		Detail map[string]any `json:"detail"`
+	}
+	assert.NoError(t, decode([]byte(err.Error()), &fromError))
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	buf, encErr := enc.EncodeEntry(zapcore.Entry{}, []zapcore.Field{{Key: "err", Type: zapcore.ObjectMarshalerType, Interface: err}})
+	assert.NoError(t, encErr)
+	var fromLog struct {
+		Err struct {
+			Detail map[string]any `json:"detail"`
+		} `json:"err"`
+	}
+	assert.NoError(t, decode(buf.Bytes(), &fromLog), buf.String())
+
+	assert.Equal(t, map[string]any{"user_id": json.Number(strconv.Itoa(id)), "user_name": "lamber"}, fromError.Detail)
+	assert.Equal(t, fromError.Detail, fromLog.Err.Detail)
+
+	mapEnc := zapcore.NewMapObjectEncoder()
+	assert.NoError(t, err.(zapcore.ObjectMarshaler).MarshalLogObject(mapEnc))
+	assert.Equal(t, fromError.Detail, mapEnc.Fields["detail"])
+}
+
+func TestOrigin(t *testing.T) {
+	err := berror.NewNotFound(nil, "not found")
+	assert.NotContains(t, err.Error(), `"origin"`)
+
+	berror.EnableOrigin(true)
+	defer berror.EnableOrigin(false)
+
+	var out struct {
+		Origin string `json:"origin"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(err.Error()), &out))
+	assert.Regexp(t, `^berror/error_test\.go:\d+ \(TestOrigin\)$`, out.Origin)
+
+	enc := zapcore.NewMapObjectEncoder()
+	assert.NoError(t, err.(zapcore.ObjectMarshaler).MarshalLogObject(enc))
+	assert.Equal(t, out.Origin, enc.Fields["origin"])
+}
+
+func TestAsError(t *testing.T) {
+	_, _, err3, err4 := generateTestError()
+	wrapped := fmt.Errorf("outer: %w", err4)
+
+	e, ok := berror.AsError(wrapped)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, err4, e)
+	assert.Equal(t, bcode.NotFound, e.Status().Code())
+
+	var target berror.Error
+	assert.Equal(t, true, errors.As(wrapped, &target))
+	assert.Equal(t, err4, target)
+
+	e3, ok := berror.AsError(fmt.Errorf("outer: %w", err3))
+	assert.Equal(t, true, ok)
+	assert.Equal(t, bcode.InternalError, e3.Status().Code())
+
+	_, ok = berror.AsError(errors.New("plain"))
+	assert.Equal(t, false, ok)
+	_, ok = berror.AsError(nil)
+	assert.Equal(t, false, ok)
+}
+
+func TestDetailDepthGuard(t *testing.T) {
+	berror.SetMaxDetailDepth(8)
+	defer berror.SetMaxDetailDepth(32)
+
+	self := map[string]any{"name": "loop"}
+	self["self"] = self
+	err := berror.New(bstatus.New(bcode.InternalError, "self-referential detail", self))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.Contains(t, err.Error(), "...(max depth exceeded)")
+
+		enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+		buf, encErr := enc.EncodeEntry(zapcore.Entry{}, []zapcore.Field{{Key: "err", Type: zapcore.ObjectMarshalerType, Interface: err}})
+		assert.NoError(t, encErr)
+		assert.Contains(t, buf.String(), "...(max depth exceeded)")
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("serialization of the self-referential detail does not terminate")
+	}
+
+	// multi-key self-reference does not expand exponentially
+	multi := map[string]any{}
+	multi["a"], multi["b"], multi["c"] = multi, multi, multi
+	err = berror.New(bstatus.New(bcode.InternalError, "multi-key self-referential detail", multi))
+	out := err.Error()
+	assert.Contains(t, out, "...(max depth exceeded)")
+	assert.Less(t, len(out), 1024)
+
+	// shared references without cycles are capped in total if the node cap is set
+	berror.SetMaxDetailNodes(10000)
+	defer berror.SetMaxDetailNodes(0)
+	shared := map[string]any{"leaf": 1}
+	for i := 0; i < 7; i++ {
+		shared = map[string]any{"w": shared, "x": shared, "y": shared, "z": shared}
+	}
+	err = berror.New(bstatus.New(bcode.InternalError, "shared detail", shared))
+	out = err.Error()
+	assert.Contains(t, out, "...(max depth exceeded)")
+	assert.Less(t, len(out), 1<<20)
+
+	// shallow details are not changed
+	shallow := map[string]any{"a": map[string]any{"b": 1}}
+	err2 := berror.New(bstatus.New(bcode.InternalError, "shallow detail", shallow))
+	assert.Contains(t, err2.Error(), `"detail":{"a":{"b":1}}`)
+
+	// wide but shallow details are not changed
+	berror.SetMaxDetailNodes(0)
+	wide := make(map[string]string, 20000)
+	for i := 0; i < 20000; i++ {
+		wide[strconv.Itoa(i)] = "v"
+	}
+	err2 = berror.New(bstatus.New(bcode.InternalError, "wide detail", wide))
+	assert.NotContains(t, err2.Error(), "...(max depth exceeded)")
+
+	// interface hops are not counted as depth
+	var nested any = "leaf"
+	for i := 0; i < 8; i++ {
+		nested = []any{nested}
+	}
+	err2 = berror.New(bstatus.New(bcode.InternalError, "nested detail", nested))
+	assert.NotContains(t, err2.Error(), "...(max depth exceeded)")
+}
+
+func TestDetailDepthGuard_StructTags(t *testing.T) {
+	berror.SetMaxDetailDepth(4)
+	defer berror.SetMaxDetailDepth(32)
+
+	type Base struct {
+		ID int `json:"id"`
+	}
+	type node struct {
+		Base
+		Name string `json:"name"`
+		Note string `json:"note,omitempty"`
+		Next *node  `json:"next,omitempty"`
+	}
+	loop := &node{Base: Base{ID: 1}, Name: "loop"}
+	loop.Next = loop
+	err := berror.New(bstatus.New(bcode.InternalError, "self-referential struct", loop))
+
+	var out map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(err.Error()), &out))
+	detail := out["detail"].(map[string]any)
+	assert.Equal(t, float64(1), detail["id"])
+	assert.Equal(t, "loop", detail["name"])
+	assert.NotContains(t, detail, "Base")
+	assert.NotContains(t, detail, "note")
+	assert.Contains(t, err.Error(), "...(max depth exceeded)")
+
+	// a struct within the limits is kept as it is
+	leaf := &node{Base: Base{ID: 2}, Name: "leaf"}
+	parent := map[string]any{"a": leaf}
+	parent["self"] = parent
+	err = berror.New(bstatus.New(bcode.InternalError, "struct detail", parent))
+	assert.Contains(t, err.Error(), `"a":{"id":2,"name":"leaf"}`)
+	assert.Contains(t, err.Error(), "...(max depth exceeded)")
+}
+
+func TestWithDetail(t *testing.T) {
+	_, err2, err3, _ := generateTestError()
+	e3 := err3.(berror.Error)
+
+	e := berror.WithDetail(e3, map[string]any{"request_id": "r-1"})
+	assert.Equal(t, e3.Status().Code(), e.Status().Code())
+	assert.Equal(t, e3.Status().Reason(), e.Status().Reason())
+	assert.Equal(t, map[string]any{"request_id": "r-1"}, e.Status().Detail())
+	assert.Equal(t, e3.Stack(), e.Stack())
+	assert.Equal(t, err2, e.Cause())
+	// the original error is not modified
+	assert.Nil(t, e3.Status().Detail())
+
+	assert.Equal(t, e3, berror.WithDetail(e3, nil))
+	assert.Nil(t, berror.WithDetail(nil, "detail"))
+}
+
+func TestWithDetail_KeepStatusType(t *testing.T) {
+	retryable := berror.New(bstatus.NewRetryable(bcode.InternalError, "lock conflict", nil))
+	e := berror.WithDetail(retryable, map[string]any{"table": "user"})
+	assert.Equal(t, true, berror.Retryable(e))
+	assert.Equal(t, map[string]any{"table": "user"}, e.Status().Detail())
+
+	template := berror.New(bstatus.NewTemplate(bcode.NotFound, "user.not_found", map[string]any{"id": 1}))
+	e = berror.WithDetail(template, "detail")
+	ts, ok := e.Status().(bstatus.TemplateStatus)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "user.not_found", ts.MessageID())
+	assert.Equal(t, map[string]any{"id": 1}, ts.Args())
+	assert.Equal(t, "detail", ts.Detail())
+
+	warning := berror.New(bstatus.NewWarning(bcode.InvalidArgument, "deprecated", nil))
+	assert.Equal(t, true, bstatus.IsWarning(berror.WithDetail(warning, "detail").Status()))
+}
+
+func TestDefaultError_MarshalJSON(t *testing.T) {
+	_, _, _, err4 := generateTestError()
+
+	type response struct {
+		Err error `json:"err"`
+	}
+	raw, err := json.Marshal(response{Err: err4})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"err":`+err4.Error()+`}`, string(raw))
+	assert.Contains(t, string(raw), `"detail":"`+testErr4detail+`"`)
+	assert.Contains(t, string(raw), `"next":{"code":500`)
+}
+
+func TestDefaultError_WithStatus(t *testing.T) {
+	_, err2, err3, _ := generateTestError()
+	e3 := err3.(berror.Error)
+
+	e := e3.WithStatus(bstatus.ServiceUnavailable)
+	assert.Equal(t, bstatus.ServiceUnavailable, e.Status())
+	assert.Equal(t, err2, e.Cause())
+	assert.Equal(t, e3.Stack(), e.Stack())
+	// the original error is not modified
+	assert.Equal(t, bstatus.InternalError, e3.Status())
+}
+
+func TestDefaultError_OriginalMessage(t *testing.T) {
+	errNoRows := errors.New("sql: no rows in result set")
+	inner := berror.NewNotFound(fmt.Errorf("query user: %w", errNoRows), "user not found")
+	outer := berror.New(bstatus.InternalError, inner)
+	assert.Equal(t, "sql: no rows in result set", outer.OriginalMessage())
+
+	assert.Equal(t, "", berror.New(bstatus.NotFound).OriginalMessage())
+}
+
+func TestSetDeterministicOutput(t *testing.T) {
+	berror.SetDeterministicOutput(true)
+
+	detail := map[string]any{"zeta": 1, "alpha": "a", "mid": true, "beta": []int{1, 2}}
+	err := berror.New(bstatus.New(bcode.InvalidArgument, "bad request", detail))
+	golden := `{"code":400,"reason":"bad request","detail":{"alpha":"a","beta":[1,2],"mid":true,"zeta":1},"next":null}`
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, golden, err.Error())
+	}
+	raw, _ := json.Marshal(err)
+	assert.Equal(t, golden, string(raw))
+
+	berror.SetDeterministicOutput(false)
+	defer berror.SetDeterministicOutput(true)
+	assert.JSONEq(t, golden, err.Error())
+}
+
+func TestSetClock(t *testing.T) {
+	frozen := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	berror.SetClock(func() time.Time { return frozen })
+	defer berror.SetClock(nil)
+
+	err := berror.New(bstatus.InternalError)
+	assert.Equal(t, frozen, err.Time())
+	assert.Equal(t, frozen, err.WithTraceID("trace").Time())
+}
+
+func TestDefaultError_AddDetailObject(t *testing.T) {
+	type fieldViolation struct {
+		Field       string `json:"field"`
+		Description string `json:"description"`
+	}
+	err := berror.New(bstatus.New(bcode.InvalidArgument, "bad request", map[string]any{"request_id": "r1"})).
+		AddDetailObject(fieldViolation{Field: "name", Description: "required"}).
+		AddDetailObject(map[string]any{"retry_after": 3})
+
+	var out map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(err.Error()), &out))
+	assert.Equal(t, map[string]any{"request_id": "r1"}, out["detail"])
+	assert.Equal(t, []any{
+		map[string]any{"field": "name", "description": "required"},
+		map[string]any{"retry_after": float64(3)},
+	}, out["details"])
+
+	enc := zapcore.NewMapObjectEncoder()
+	assert.NoError(t, err.(zapcore.ObjectMarshaler).MarshalLogObject(enc))
+	assert.Len(t, enc.Fields["details"], 2)
+
+	// the details array is omitted when empty
+	assert.NotContains(t, berror.New(bstatus.NotFound).Error(), `"details"`)
+}
+
+func TestWrapMessage(t *testing.T) {
+	inner := berror.NewNotFound(nil, "user not found")
+	err := berror.WrapMessage(inner, "load profile failed")
+	assert.Equal(t, bcode.NotFound, err.Status().Code())
+	assert.Equal(t, "load profile failed", err.Status().Reason())
+	assert.Equal(t, inner, err.Cause())
+
+	err = berror.WrapMessage(errors.New("connection refused"), "load profile failed")
+	assert.Equal(t, bcode.InternalError, err.Status().Code())
+	assert.Equal(t, "load profile failed", err.Status().Reason())
+
+	assert.Nil(t, berror.WrapMessage(nil, "load profile failed"))
+}
@@ -0,0 +1,28 @@
+package berror
+
+import (
+	"sync"
+
+	"github.com/lamber92/go-brick/berror/bcode"
+)
+
+// defHelpURLs the default documentation url of the error code, bcode.Code -> string
+var defHelpURLs = sync.Map{}
+
+// RegisterHelpURL register the default documentation url of the error code,
+// used when the error does not carry its own url.
+func RegisterHelpURL(code bcode.Code, url string) {
+	defHelpURLs.Store(code, url)
+}
+
+// GetHelpURL get the default documentation url of the error code.
+// the unregistered one returns an empty string.
+func GetHelpURL(code bcode.Code) string {
+	if code == nil {
+		return ""
+	}
+	if v, ok := defHelpURLs.Load(code); ok {
+		return v.(string)
+	}
+	return ""
+}
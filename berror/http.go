@@ -0,0 +1,50 @@
+package berror
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/lamber92/go-brick/berror/bcode"
+)
+
+// httpStatusMapping error code -> http transport status code, bcode.Code -> int
+var httpStatusMapping = sync.Map{}
+
+func init() {
+	for code, status := range map[bcode.Code]int{
+		bcode.OK:                 http.StatusOK,
+		bcode.InvalidArgument:    http.StatusBadRequest,
+		bcode.Unauthorized:       http.StatusUnauthorized,
+		bcode.Forbidden:          http.StatusForbidden,
+		bcode.NotFound:           http.StatusNotFound,
+		bcode.RequestTimeout:     http.StatusRequestTimeout,
+		bcode.AlreadyExists:      http.StatusConflict,
+		bcode.ClientClosed:       499,
+		bcode.InternalError:      http.StatusInternalServerError,
+		bcode.ServiceUnavailable: http.StatusServiceUnavailable,
+		bcode.GatewayTimeout:     http.StatusGatewayTimeout,
+		bcode.Unknown:            http.StatusInternalServerError,
+	} {
+		httpStatusMapping.Store(code, status)
+	}
+}
+
+// RegisterHTTPStatus register or overwrite the http transport status code of the error code
+func RegisterHTTPStatus(code bcode.Code, status int) {
+	httpStatusMapping.Store(code, status)
+}
+
+// HTTPStatus get the http transport status code of the error.
+// nil, non-Error errors and unregistered codes fall back to 500.
+//
+// nb. it differs from bcode.ToHTTPStatusCode, which converts to the code in the http response body.
+func HTTPStatus(err error) int {
+	e, ok := AsError(err)
+	if !ok || e.Status() == nil {
+		return http.StatusInternalServerError
+	}
+	if v, ok := httpStatusMapping.Load(e.Status().Code()); ok {
+		return v.(int)
+	}
+	return http.StatusInternalServerError
+}
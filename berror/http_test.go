@@ -0,0 +1,38 @@
+package berror_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{berror.NewInvalidArgument(nil, "bad"), http.StatusBadRequest},
+		{berror.NewNotFound(nil, "not found"), http.StatusNotFound},
+		{berror.NewRequestTimeout(nil, "timeout"), http.StatusRequestTimeout},
+		{berror.NewAlreadyExists(nil, "exists"), http.StatusConflict},
+		{berror.NewClientClose(nil, "closed"), 499},
+		{berror.NewGatewayTimeout(nil, "timeout"), http.StatusGatewayTimeout},
+		{berror.NewInternalError(nil, "internal"), http.StatusInternalServerError},
+		{berror.New(bstatus.Unknown), http.StatusInternalServerError},
+		{berror.New(bstatus.New(bcode.New(55555), "unregistered", nil)), http.StatusInternalServerError},
+		{errors.New("plain"), http.StatusInternalServerError},
+		{nil, http.StatusInternalServerError},
+	}
+	for i, c := range cases {
+		assert.Equal(t, c.status, berror.HTTPStatus(c.err), i)
+	}
+
+	code := bcode.New(55556)
+	berror.RegisterHTTPStatus(code, http.StatusTooManyRequests)
+	assert.Equal(t, http.StatusTooManyRequests, berror.HTTPStatus(berror.New(bstatus.New(code, "too many", nil))))
+}
@@ -0,0 +1,89 @@
+package berror
+
+import (
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"go.uber.org/zap/zapcore"
+)
+
+// Join aggregate multiple errors into one Error, e.g. independent validation failures.
+// the joined Error takes the status of the first child carrying one,
+// Errors() exposes all children, and Error() renders the list of each child's summary as the next level.
+// nil entries are skipped, returns nil if there is no non-nil error,
+// and returns the error itself if only one Error remains.
+func Join(errs ...error) Error {
+	children := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if !isNilError(err) {
+			children = append(children, err)
+		}
+	}
+	if len(children) == 0 {
+		return nil
+	}
+	if len(children) == 1 {
+		if e, ok := children[0].(Error); ok {
+			return e
+		}
+	}
+	var status bstatus.Status = bstatus.Unknown
+	for _, child := range children {
+		if e, ok := AsError(child); ok && e.Status() != nil {
+			status = e.Status()
+			break
+		}
+	}
+	return newError(&joinedErrors{errs: children}, status, 1)
+}
+
+// joinedErrors the children of a joined Error
+type joinedErrors struct {
+	errs []error
+}
+
+// Error output the list of each child's summary
+func (j *joinedErrors) Error() string {
+	str, _ := jsonStdIter.MarshalToString(j.summarize(visitedSet{}))
+	return str
+}
+
+// Unwrap provides compatibility for Go 1.20 multiple error chains.
+func (j *joinedErrors) Unwrap() []error {
+	return j.errs
+}
+
+func (j *joinedErrors) summarize(visited visitedSet) []any {
+	out := make([]any, 0, len(j.errs))
+	for _, err := range j.errs {
+		switch {
+		case visited.reaches(err):
+			out = append(out, cycleMarker)
+		case isDefaultError(err):
+			out = append(out, err.(*defaultError).summarize(visited))
+		default:
+			out = append(out, err.Error())
+		}
+	}
+	return out
+}
+
+func (j *joinedErrors) marshalLogArray(enc zapcore.ArrayEncoder, visited visitedSet) error {
+	for _, err := range j.errs {
+		switch {
+		case visited.reaches(err):
+			enc.AppendString(cycleMarker)
+		case isDefaultError(err):
+			child := err.(*defaultError)
+			_ = enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+				return child.marshalLogObject(enc, visited)
+			}))
+		default:
+			enc.AppendString(err.Error())
+		}
+	}
+	return nil
+}
+
+func isDefaultError(err error) bool {
+	_, ok := err.(*defaultError)
+	return ok
+}
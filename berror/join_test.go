@@ -0,0 +1,81 @@
+package berror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestJoin(t *testing.T) {
+	err1 := berror.NewInvalidArgument(nil, "name is required")
+	err2 := errors.New("plain error")
+	err3 := berror.NewNotFound(nil, "group not found")
+
+	joined := berror.Join(nil, err1, err2, nil, err3)
+	assert.Equal(t, bcode.InvalidArgument, joined.Status().Code())
+	assert.Equal(t, []error{err1, err2, err3}, joined.Errors())
+	assert.Equal(t, []error{err1, err2, err3}, joined.Causes())
+
+	var sum map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(joined.Error()), &sum), joined.Error())
+	assert.Equal(t, float64(bcode.InvalidArgument.ToInt()), sum["code"])
+	out := sum["next"].([]any)
+	assert.Equal(t, 3, len(out))
+	assert.Equal(t, float64(bcode.InvalidArgument.ToInt()), out[0].(map[string]any)["code"])
+	assert.Equal(t, "plain error", out[1])
+	assert.Equal(t, "group not found", out[2].(map[string]any)["reason"])
+
+	// errors.Is/As see all children
+	assert.ErrorIs(t, joined, err2)
+	assert.ErrorIs(t, joined, err3)
+	assert.Equal(t, true, berror.IsCode(joined, bcode.InvalidArgument))
+
+	// wrapped joined error renders the children as the next level
+	wrapped := berror.NewInternalError(joined, "validation failed")
+	assert.Contains(t, wrapped.Error(), `"next":[{"code":400`)
+}
+
+func TestJoin_Single(t *testing.T) {
+	err1 := berror.NewInvalidArgument(nil, "name is required")
+	assert.Equal(t, err1, berror.Join(nil, err1, nil))
+	assert.Nil(t, berror.Join())
+	assert.Nil(t, berror.Join(nil, nil))
+}
+
+func TestJoin_WithTraceID(t *testing.T) {
+	joined := berror.Join(berror.NewInvalidArgument(nil, "name is required"), errors.New("plain error")).
+		WithTraceID("trace-1")
+
+	var sum map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(joined.Error()), &sum), joined.Error())
+	assert.Equal(t, "trace-1", sum["trace_id"])
+
+	enc := zapcore.NewMapObjectEncoder()
+	assert.NoError(t, joined.(zapcore.ObjectMarshaler).MarshalLogObject(enc))
+	assert.Equal(t, "trace-1", enc.Fields["trace_id"])
+}
+
+func TestJoin_SharedCause(t *testing.T) {
+	base := berror.NewNotFound(errors.New("root"), "")
+	joined := berror.Join(
+		berror.New(bstatus.New(bcode.InvalidArgument, "", nil), base),
+		berror.New(bstatus.New(bcode.InternalError, "", nil), base),
+	)
+	assert.NotContains(t, joined.Error(), "<cycle>")
+
+	enc := zapcore.NewMapObjectEncoder()
+	assert.NoError(t, joined.(zapcore.ObjectMarshaler).MarshalLogObject(enc))
+	children := enc.Fields["next"].([]any)
+	assert.Equal(t, 2, len(children))
+	for _, child := range children {
+		next := child.(map[string]any)["next"].(map[string]any)
+		assert.Equal(t, bcode.NotFound.ToInt(), next["code"])
+		assert.Equal(t, "root", next["next"])
+	}
+}
@@ -0,0 +1,22 @@
+package berror
+
+import "errors"
+
+// Labels collect the labels attached to every level of the error chain,
+// the labels of outer levels take precedence over inner ones.
+func Labels(err error) map[string]string {
+	var chain []*defaultError
+	for depth := 0; err != nil && depth < maxChainDepth; depth++ {
+		if e, ok := err.(*defaultError); ok && e != nil {
+			chain = append(chain, e)
+		}
+		err = errors.Unwrap(err)
+	}
+	labels := make(map[string]string)
+	for i := len(chain) - 1; i >= 0; i-- {
+		for k, v := range chain[i].labels {
+			labels[k] = v
+		}
+	}
+	return labels
+}
@@ -0,0 +1,35 @@
+package berror_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabels(t *testing.T) {
+	inner := berror.New(bstatus.NotFound).WithLabels(map[string]string{
+		"operation":     "get_user",
+		"resource_type": "user",
+	})
+	outer := berror.New(bstatus.InternalError, inner).WithLabels(map[string]string{
+		"operation": "load_profile",
+	})
+	wrapped := fmt.Errorf("wrap: %w", outer)
+
+	assert.Equal(t, map[string]string{
+		"operation":     "load_profile",
+		"resource_type": "user",
+	}, berror.Labels(wrapped))
+
+	// labels are not serialized
+	assert.NotContains(t, outer.Error(), "resource_type")
+
+	// WithLabels does not modify the original error
+	base := berror.New(bstatus.NotFound)
+	_ = base.WithLabels(map[string]string{"operation": "get_user"})
+	assert.Equal(t, map[string]string{}, berror.Labels(base))
+	assert.Equal(t, map[string]string{}, berror.Labels(nil))
+}
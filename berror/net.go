@@ -0,0 +1,52 @@
+package berror
+
+import (
+	"errors"
+	"net"
+
+	"github.com/lamber92/go-brick/berror/bcode"
+)
+
+// make sure *defaultError can be used where a net.Error is expected.
+// nb. every brick error is a net.Error: errors.As(err, &netErr) succeeds for any brick error,
+// including the ones unrelated to the network(e.g. NotFound), so it cannot tell a network error apart.
+// use Timeout()/Temporary() as hints only, and check the code(e.g. IsCode) to classify the error.
+var _ net.Error = (*defaultError)(nil)
+
+// Timeout net.Error impl.
+// returns true if the error code is a timeout code,
+// or the wrapped error is a net.Error and reports a timeout.
+func (d *defaultError) Timeout() bool {
+	if d == nil {
+		return false
+	}
+	if d.status != nil && isTimeoutCode(d.status.Code()) {
+		return true
+	}
+	var ne net.Error
+	if errors.As(d.err, &ne) {
+		return ne.Timeout()
+	}
+	return false
+}
+
+// Temporary net.Error impl.
+// returns true if the error code is a timeout or unavailable code,
+// or the wrapped error is a net.Error and reports a temporary error.
+func (d *defaultError) Temporary() bool {
+	if d == nil {
+		return false
+	}
+	if d.status != nil && (isTimeoutCode(d.status.Code()) || d.status.Code().Is(bcode.ServiceUnavailable)) {
+		return true
+	}
+	var ne net.Error
+	if errors.As(d.err, &ne) {
+		return ne.Temporary()
+	}
+	return false
+}
+
+func isTimeoutCode(code bcode.Code) bool {
+	return code.Is(bcode.RequestTimeout) || code.Is(bcode.GatewayTimeout)
+}
@@ -0,0 +1,45 @@
+package berror_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeNetError struct {
+	timeout   bool
+	temporary bool
+}
+
+func (e *fakeNetError) Error() string   { return "fake net error" }
+func (e *fakeNetError) Timeout() bool   { return e.timeout }
+func (e *fakeNetError) Temporary() bool { return e.temporary }
+
+func TestNetError(t *testing.T) {
+	// delegate to the wrapped net error
+	err1 := berror.NewInternalError(&fakeNetError{timeout: true, temporary: true}, "dial failed")
+	var ne net.Error
+	assert.Equal(t, true, errors.As(err1, &ne))
+	assert.Equal(t, true, ne.Timeout())
+	assert.Equal(t, true, ne.Temporary())
+
+	err2 := berror.NewInternalError(&fakeNetError{}, "dial failed")
+	assert.Equal(t, true, errors.As(err2, &ne))
+	assert.Equal(t, false, ne.Timeout())
+	assert.Equal(t, false, ne.Temporary())
+
+	// derived from the code
+	err3 := berror.NewGatewayTimeout(errors.New("slow upstream"), "timeout")
+	assert.Equal(t, true, errors.As(err3, &ne))
+	assert.Equal(t, true, ne.Timeout())
+	assert.Equal(t, true, ne.Temporary())
+
+	// any brick error is a net.Error, even if it is unrelated to the network
+	err4 := berror.NewNotFound(nil, "not found")
+	assert.Equal(t, true, errors.As(err4, &ne))
+	assert.Equal(t, false, ne.Timeout())
+	assert.Equal(t, false, ne.Temporary())
+}
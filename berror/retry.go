@@ -0,0 +1,93 @@
+package berror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/lamber92/go-brick/berror/bstatus"
+)
+
+// retryableCodes the error codes considered transient by default
+var retryableCodes = map[bcode.Code]bool{
+	bcode.RequestTimeout:     true,
+	bcode.ServiceUnavailable: true,
+	bcode.GatewayTimeout:     true,
+}
+
+// RegisterRetryableCode register or overwrite whether the error code is retryable by default
+func RegisterRetryableCode(code bcode.Code, retryable bool) {
+	retryableCodes[code] = retryable
+}
+
+// Retryable determine whether the error is transient and worth retrying.
+// it unwraps the chain from the outermost level:
+// a level explicitly marked by bstatus.NewRetryable/NewNonRetryable decides the result,
+// otherwise the error is retryable if any level has a retryable code,
+// e.g. RequestTimeout and GatewayTimeout are retryable, InvalidArgument and NotFound are not.
+func Retryable(err error) bool {
+	for depth := 0; err != nil && depth < maxChainDepth; depth++ {
+		if e, ok := err.(Error); ok && e.Status() != nil {
+			if retryable, marked := bstatus.IsMarkedRetryable(e.Status()); marked {
+				return retryable
+			}
+			if retryableCodes[e.Status().Code()] {
+				return true
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// IsRetryable the alias of Retryable
+func IsRetryable(err error) bool {
+	return Retryable(err)
+}
+
+// Retry call fn until it succeeds, returns a non-retryable error, or runs out of attempts.
+// @backoff: returns the waiting duration before the n-th retry(starting from 1), nil means no waiting.
+//
+// non-retryable errors are returned immediately as-is.
+// on final failure or context cancellation, a non-retryable error wrapping the last error
+// with the number of attempts in the detail is returned.
+func Retry(ctx context.Context, attempts int, backoff func(int) time.Duration, fn func() error) error {
+	if attempts <= 0 {
+		attempts = 1
+	}
+	var lastErr error
+	for i := 1; ; i++ {
+		if lastErr = fn(); lastErr == nil {
+			return nil
+		}
+		if !Retryable(lastErr) {
+			return lastErr
+		}
+		if i >= attempts {
+			return newRetryError(lastErr, fmt.Sprintf("failed after %d attempts", i), i)
+		}
+		var wait time.Duration
+		if backoff != nil {
+			wait = backoff(i)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return newRetryError(lastErr, fmt.Sprintf("aborted after %d attempts: %v", i, ctx.Err()), i)
+		case <-timer.C:
+		}
+	}
+}
+
+// newRetryError wrap the last error and keep its code,
+// the result is marked as not retryable so that an outer Retry does not retry it again.
+func newRetryError(lastErr error, reason string, attempts int) Error {
+	var code bcode.Code = bcode.Unknown
+	if e, ok := AsError(lastErr); ok && e.Status() != nil {
+		code = e.Status().Code()
+	}
+	return NewWithSkip(lastErr, bstatus.NewNonRetryable(code, reason, map[string]any{"attempts": attempts}), 2)
+}
@@ -0,0 +1,113 @@
+package berror_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry_SucceedOnThirdTry(t *testing.T) {
+	calls := 0
+	err := berror.Retry(context.Background(), 5, func(int) time.Duration { return time.Millisecond }, func() error {
+		calls++
+		if calls < 3 {
+			return berror.NewGatewayTimeout(nil, "upstream timeout")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetry_NonRetryable(t *testing.T) {
+	calls := 0
+	notFound := berror.NewNotFound(nil, "not found")
+	err := berror.Retry(context.Background(), 5, nil, func() error {
+		calls++
+		return notFound
+	})
+	assert.Equal(t, notFound, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetry_Exhausted(t *testing.T) {
+	calls := 0
+	timeout := berror.NewRequestTimeout(nil, "timeout")
+	err := berror.Retry(context.Background(), 3, nil, func() error {
+		calls++
+		return timeout
+	})
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, true, berror.IsCode(err, bcode.RequestTimeout))
+	assert.ErrorIs(t, err, timeout)
+	e, _ := berror.AsError(err)
+	assert.Equal(t, map[string]any{"attempts": 3}, e.Status().Detail())
+	// the exhausted error is not retried again
+	assert.Equal(t, false, berror.Retryable(err))
+}
+
+func TestRetry_Nested(t *testing.T) {
+	calls := 0
+	err := berror.Retry(context.Background(), 2, nil, func() error {
+		return berror.Retry(context.Background(), 3, nil, func() error {
+			calls++
+			return berror.NewGatewayTimeout(nil, "timeout")
+		})
+	})
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, true, berror.IsCode(err, bcode.GatewayTimeout))
+}
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := berror.Retry(ctx, 5, func(int) time.Duration { return time.Hour }, func() error {
+		calls++
+		cancel()
+		return berror.NewGatewayTimeout(nil, "timeout")
+	})
+	assert.Equal(t, 1, calls)
+	e, ok := berror.AsError(err)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, map[string]any{"attempts": 1}, e.Status().Detail())
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.Equal(t, true, berror.IsRetryable(berror.NewGatewayTimeout(nil, "timeout")))
+	assert.Equal(t, false, berror.IsRetryable(berror.NewNotFound(nil, "not found")))
+	assert.Equal(t, true, berror.IsRetryable(berror.NewInternalError(berror.NewRequestTimeout(nil, "timeout"), "wrapped")))
+	assert.Equal(t, false, berror.IsRetryable(errors.New("plain")))
+	assert.Equal(t, false, berror.IsRetryable(nil))
+}
+
+func TestRetryable(t *testing.T) {
+	assert.Equal(t, true, berror.Retryable(berror.NewRequestTimeout(nil, "timeout")))
+	assert.Equal(t, true, berror.Retryable(berror.NewGatewayTimeout(nil, "timeout")))
+	assert.Equal(t, false, berror.Retryable(berror.NewInvalidArgument(nil, "bad")))
+	assert.Equal(t, false, berror.Retryable(berror.NewNotFound(nil, "not found")))
+
+	// marked status
+	marked := berror.New(bstatus.NewRetryable(bcode.InternalError, "lock conflict", nil))
+	assert.Equal(t, true, berror.Retryable(marked))
+
+	// unwrap through the chain
+	wrapped := fmt.Errorf("outer: %w", berror.NewNotFound(marked, "wrapped"))
+	assert.Equal(t, true, berror.Retryable(wrapped))
+
+	// an outer level explicitly marked as not retryable overrides the inner levels
+	exhausted := berror.New(bstatus.NewNonRetryable(bcode.GatewayTimeout, "gave up", nil), berror.NewGatewayTimeout(nil, "timeout"))
+	assert.Equal(t, false, berror.Retryable(exhausted))
+
+	// custom code
+	code := bcode.New(44444)
+	berror.RegisterRetryableCode(code, true)
+	defer berror.RegisterRetryableCode(code, false)
+	assert.Equal(t, true, berror.Retryable(berror.New(bstatus.New(code, "custom", nil))))
+}
@@ -0,0 +1,63 @@
+package berror
+
+import (
+	"errors"
+	"reflect"
+)
+
+// RootCause returns the innermost error of the chain,
+// following Unwrap() until there is no further cause.
+// returns nil for nil.
+func RootCause(err error) error {
+	for depth := 0; err != nil && depth < maxChainDepth; depth++ {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+	return err
+}
+
+// RootMatch how SameRoot compares the root causes
+type RootMatch int
+
+const (
+	// RootEquivalent the root causes are the same object,
+	// or have the same code and reason(brick errors) or the same Error() output(other errors).
+	RootEquivalent RootMatch = iota
+	// RootIdentity the root causes must be the same object.
+	RootIdentity
+)
+
+// SameRoot determine whether the two errors stem from the same root cause.
+// @match decides identity-vs-equivalence, RootEquivalent by default.
+func SameRoot(a, b error, match ...RootMatch) bool {
+	ra, rb := RootCause(a), RootCause(b)
+	if ra == nil || rb == nil {
+		return ra == nil && rb == nil
+	}
+	if identical(ra, rb) {
+		return true
+	}
+	if len(match) > 0 && match[0] == RootIdentity {
+		return false
+	}
+	ea, ok1 := ra.(Error)
+	eb, ok2 := rb.(Error)
+	if ok1 != ok2 {
+		return false
+	}
+	if !ok1 {
+		return reflect.TypeOf(ra) == reflect.TypeOf(rb) && ra.Error() == rb.Error()
+	}
+	return sameCodeAndReason(ea.Status(), eb.Status())
+}
+
+// identical determine whether the two errors are the same object.
+func identical(a, b error) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
@@ -0,0 +1,40 @@
+package berror_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootCause(t *testing.T) {
+	root := errors.New("connection refused")
+	err := berror.New(bstatus.InternalError, fmt.Errorf("dial: %w", root))
+	assert.Equal(t, root, berror.RootCause(err))
+
+	leaf := berror.New(bstatus.NotFound)
+	assert.Equal(t, leaf, berror.RootCause(leaf))
+	assert.Nil(t, berror.RootCause(nil))
+}
+
+func TestSameRoot(t *testing.T) {
+	root := errors.New("connection refused")
+	a := berror.New(bstatus.InternalError, fmt.Errorf("dial: %w", root))
+	b := berror.New(bstatus.ServiceUnavailable, berror.New(bstatus.GatewayTimeout, root))
+	assert.Equal(t, true, berror.SameRoot(a, b))
+	assert.Equal(t, true, berror.SameRoot(a, b, berror.RootIdentity))
+
+	// equivalent but not identical roots
+	c := berror.New(bstatus.InternalError, berror.New(bstatus.NotFound))
+	d := fmt.Errorf("wrap: %w", berror.New(bstatus.NotFound))
+	assert.Equal(t, true, berror.SameRoot(c, d))
+	assert.Equal(t, false, berror.SameRoot(c, d, berror.RootIdentity))
+	assert.Equal(t, true, berror.SameRoot(errors.New("eof"), errors.New("eof")))
+
+	assert.Equal(t, false, berror.SameRoot(a, c))
+	assert.Equal(t, false, berror.SameRoot(a, nil))
+	assert.Equal(t, true, berror.SameRoot(nil, nil))
+}
@@ -0,0 +1,40 @@
+package berror
+
+import (
+	"github.com/lamber92/go-brick/berror/bcode"
+	"github.com/lamber92/go-brick/berror/bstatus"
+)
+
+// Sentinel errors of the built-in codes, used as errors.Is targets,
+// e.g. errors.Is(err, berror.ErrNotFound) matches any NotFound level in the chain.
+// they carry only the code with an empty reason.
+var (
+	ErrUnknown            = newSentinel(bcode.Unknown)
+	ErrInvalidArgument    = newSentinel(bcode.InvalidArgument)
+	ErrUnauthorized       = newSentinel(bcode.Unauthorized)
+	ErrForbidden          = newSentinel(bcode.Forbidden)
+	ErrNotFound           = newSentinel(bcode.NotFound)
+	ErrRequestTimeout     = newSentinel(bcode.RequestTimeout)
+	ErrClientClosed       = newSentinel(bcode.ClientClosed)
+	ErrInternalError      = newSentinel(bcode.InternalError)
+	ErrServiceUnavailable = newSentinel(bcode.ServiceUnavailable)
+	ErrGatewayTimeout     = newSentinel(bcode.GatewayTimeout)
+	ErrAlreadyExists      = newSentinel(bcode.AlreadyExists)
+)
+
+func newSentinel(code bcode.Code) Error {
+	return &defaultError{
+		status:   bstatus.New(code, "", nil),
+		sentinel: true,
+	}
+}
+
+// Is errors.Is impl, the error matches a sentinel error of the same code.
+// errors.Is unwraps the chain, so a deeply wrapped level also matches.
+func (d *defaultError) Is(target error) bool {
+	t, ok := target.(*defaultError)
+	if !ok || t == nil || !t.sentinel || d == nil || d.status == nil {
+		return false
+	}
+	return d.status.Code().ToInt() == t.status.Code().ToInt()
+}
@@ -0,0 +1,33 @@
+package berror_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentinel(t *testing.T) {
+	err := berror.NewNotFound(nil, "user not found")
+	assert.Equal(t, true, errors.Is(err, berror.ErrNotFound))
+	assert.Equal(t, false, errors.Is(err, berror.ErrInternalError))
+
+	// a deeply wrapped level still matches
+	wrapped := fmt.Errorf("handler: %w", berror.New(bstatus.InternalError, berror.New(bstatus.Unknown, err)))
+	assert.Equal(t, true, errors.Is(wrapped, berror.ErrNotFound))
+	assert.Equal(t, true, errors.Is(wrapped, berror.ErrInternalError))
+	assert.Equal(t, false, errors.Is(wrapped, berror.ErrGatewayTimeout))
+
+	// only sentinels are matched by code
+	assert.Equal(t, false, errors.Is(err, berror.NewNotFound(nil, "another")))
+	assert.Equal(t, "", berror.ErrNotFound.Status().Reason())
+
+	// copies of a sentinel are ordinary errors
+	copied := berror.ErrNotFound.WithTraceID("trace")
+	assert.Equal(t, false, errors.Is(err, copied))
+	assert.Equal(t, false, errors.Is(err, berror.WithDetail(berror.ErrNotFound, "detail")))
+	assert.Equal(t, true, errors.Is(copied, berror.ErrNotFound))
+}
@@ -0,0 +1,123 @@
+package berror
+
+import (
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxDetailSize the max serialized size(in bytes) of the detail,
+// details larger than it will be truncated when formatting or logging.
+// <= 0 means no limit.
+var maxDetailSize = 0
+
+// SetMaxDetailSize set the max serialized size(in bytes) of the detail.
+// oversized details are truncated with a marker in Error() and log output,
+// the original error still keeps the full detail.
+// size <= 0 disables truncation.
+// nb. if you need this setting, call it when you initialize the program.
+func SetMaxDetailSize(size int) {
+	maxDetailSize = size
+}
+
+// globalDetail the detail entries merged into every error's serialized detail.
+var globalDetail map[string]any
+
+// SetGlobalDetail set the detail entries(e.g. service name, version, host)
+// merged into every error's serialized detail at format/log time.
+// the entries have the lowest precedence and are overridden by per-error keys.
+// they are only merged when the error's own detail is nil or a map[string]any.
+// nb. if you need this setting, call it when you initialize the program.
+func SetGlobalDetail(detail map[string]any) {
+	if len(detail) == 0 {
+		globalDetail = nil
+		return
+	}
+	globalDetail = make(map[string]any, len(detail))
+	for k, v := range detail {
+		globalDetail[k] = v
+	}
+}
+
+// collapseSameCode whether to collapse the wrapping level with the same code and reason.
+var collapseSameCode = false
+
+// SetCollapseSameCode set whether New/NewWithSkip collapse the wrapping level
+// when the immediate inner error has the same code and reason.
+// the collapsed error keeps one level and merges the detail.
+// nb. if you need this setting, call it when you initialize the program.
+func SetCollapseSameCode(collapse bool) {
+	collapseSameCode = collapse
+}
+
+// originOutput whether to render the "origin" field derived from the stack.
+var originOutput = false
+
+// EnableOrigin set whether Error() and log output render an "origin" field,
+// e.g. "user/service.go:42 (GetUser)", derived from the innermost frame of the captured stack.
+// nb. if you need this setting, call it when you initialize the program.
+func EnableOrigin(enable bool) {
+	originOutput = enable
+}
+
+// maxDetailDepth the max nesting depth of the detail when serializing,
+// deeper levels are cut off with a marker. <= 0 means no limit.
+var maxDetailDepth = 32
+
+// SetMaxDetailDepth set the max nesting depth of the detail when serializing,
+// protecting the log pipeline from deeply nested or self-referential details.
+// depth <= 0 disables the guard.
+// nb. if you need this setting, call it when you initialize the program.
+func SetMaxDetailDepth(depth int) {
+	maxDetailDepth = depth
+}
+
+// maxDetailNodes the max number of values visited when guarding the detail depth. <= 0 means no limit.
+var maxDetailNodes = 0
+
+// SetMaxDetailNodes set the max number of values visited when guarding the detail depth,
+// protecting against details whose shared references expand exponentially,
+// values beyond it are cut off with the same marker as the depth guard.
+// it only takes effect with the depth guard enabled(see SetMaxDetailDepth), n <= 0 disables it.
+// nb. if you need this setting, call it when you initialize the program.
+func SetMaxDetailNodes(n int) {
+	maxDetailNodes = n
+}
+
+// stackCaptureThreshold the min level of the error code whose stack is captured.
+var stackCaptureThreshold = zapcore.DebugLevel
+
+// SetStackCaptureThreshold set the min level for New/NewWithSkip to capture the stack,
+// errors whose code level(see bcode.GetLevel) is below it get an empty stack,
+// e.g. zapcore.ErrorLevel keeps stacks for critical errors only and client errors stay cheap.
+// nb. if you need this setting, call it when you initialize the program.
+func SetStackCaptureThreshold(level zapcore.Level) {
+	stackCaptureThreshold = level
+}
+
+// SetDeterministicOutput set whether Error() and MarshalJSON sort the map keys(e.g. of the detail),
+// keeping the serialized output stable for golden/snapshot tests.
+// the output is deterministic by default, passing false skips the sorting for performance.
+// log output relies on the encoder, zap's JSON encoder always sorts the map keys.
+// nb. if you need this setting, call it when you initialize the program.
+func SetDeterministicOutput(deterministic bool) {
+	if deterministic {
+		jsonStdIter = jsoniter.ConfigCompatibleWithStandardLibrary
+	} else {
+		jsonStdIter = jsonFastIter
+	}
+}
+
+// clock the function used to read the current time
+var clock = time.Now
+
+// SetClock set the function used wherever the package reads the current time,
+// e.g. the creation time of errors. nil restores time.Now.
+// it is mainly used to freeze the time in tests.
+func SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	clock = now
+}
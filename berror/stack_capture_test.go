@@ -0,0 +1,38 @@
+package berror_test
+
+import (
+	"testing"
+
+	"github.com/lamber92/go-brick/berror"
+	"github.com/lamber92/go-brick/berror/bstatus"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSetStackCaptureThreshold(t *testing.T) {
+	berror.SetStackCaptureThreshold(zapcore.ErrorLevel)
+	defer berror.SetStackCaptureThreshold(zapcore.DebugLevel)
+
+	assert.Empty(t, berror.New(bstatus.InvalidArgument).Stack())
+	assert.Empty(t, berror.New(bstatus.NotFound).Stack())
+	assert.NotEmpty(t, berror.New(bstatus.InternalError).Stack())
+
+	// a server error wrapping a client error still captures the stack
+	inner := berror.New(bstatus.NotFound)
+	assert.NotEmpty(t, berror.New(bstatus.InternalError, inner).Stack())
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.Run("capture", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = berror.New(bstatus.InvalidArgument)
+		}
+	})
+	b.Run("threshold", func(b *testing.B) {
+		berror.SetStackCaptureThreshold(zapcore.ErrorLevel)
+		defer berror.SetStackCaptureThreshold(zapcore.DebugLevel)
+		for i := 0; i < b.N; i++ {
+			_ = berror.New(bstatus.InvalidArgument)
+		}
+	})
+}
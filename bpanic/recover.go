@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"runtime"
 	"strings"
 
 	"github.com/lamber92/go-brick/berror"
@@ -15,6 +16,19 @@ import (
 
 const recoverReason = "recover"
 
+// panicTypeLabel the label key of the classified panic type
+const panicTypeLabel = "panic_type"
+
+// panic type label values
+const (
+	PanicTypeNilPointer      = "nil_pointer"
+	PanicTypeIndexOutOfRange = "index_out_of_range"
+	PanicTypeTypeAssertion   = "type_assertion"
+	PanicTypeRuntime         = "runtime"
+	PanicTypeNetwork         = "network"
+	PanicTypeBusiness        = "business"
+)
+
 var _identifyErr = defaultIdentify
 
 func ReplaceRecoverIdentify(f func(r any, hook func(err error))) {
@@ -35,8 +49,9 @@ func SimpleHook(err error) {
 // defaultIdentify the default processing method for identifying panic reasons
 func defaultIdentify(r any, hook func(error)) {
 	var (
-		err    error
-		status bstatus.Status
+		err       error
+		status    bstatus.Status
+		panicType = PanicTypeBusiness
 	)
 	if hook == nil {
 		hook = SimpleHook
@@ -47,6 +62,7 @@ func defaultIdentify(r any, hook func(error)) {
 	// ignore specific network errors
 	case *net.OpError:
 		err = tmp
+		panicType = PanicTypeNetwork
 		// Check for a broken connection, as it is not really a
 		// condition that warrants a panic stack trace.
 		if se, ok := tmp.Err.(*os.SyscallError); ok {
@@ -60,6 +76,10 @@ func defaultIdentify(r any, hook func(error)) {
 	case string:
 		err = errors.New(tmp)
 		status = bstatus.New(bcode.InternalError, recoverReason, ".(type)=string")
+	case runtime.Error:
+		err = tmp
+		panicType = classifyRuntimeError(tmp)
+		status = bstatus.New(bcode.InternalError, recoverReason, ".(type)=runtime.Error")
 	case error:
 		err = tmp
 		status = bstatus.New(bcode.InternalError, recoverReason, ".(type)=error")
@@ -68,6 +88,21 @@ func defaultIdentify(r any, hook func(error)) {
 		status = bstatus.New(bcode.InternalError, recoverReason, fmt.Sprintf(".(type)=%T", tmp))
 	}
 	// convert to internal error and skip stacktrace layer
-	err = berror.NewWithSkip(err, status, 3)
+	err = berror.NewWithSkip(err, status, 3).WithLabels(map[string]string{panicTypeLabel: panicType})
 	hook(err)
 }
+
+// classifyRuntimeError distinguish the common runtime bug classes
+func classifyRuntimeError(err runtime.Error) string {
+	if _, ok := err.(*runtime.TypeAssertionError); ok {
+		return PanicTypeTypeAssertion
+	}
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "nil pointer dereference"):
+		return PanicTypeNilPointer
+	case strings.Contains(msg, "index out of range"), strings.Contains(msg, "slice bounds out of range"):
+		return PanicTypeIndexOutOfRange
+	}
+	return PanicTypeRuntime
+}
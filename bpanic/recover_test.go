@@ -4,8 +4,10 @@ import (
 	"context"
 	"testing"
 
+	"github.com/lamber92/go-brick/berror"
 	"github.com/lamber92/go-brick/blog"
 	"github.com/lamber92/go-brick/bpanic"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestRecover(t *testing.T) {
@@ -18,3 +20,30 @@ func TestRecover(t *testing.T) {
 	f()
 	// {"level":"WARN","time":"2023-05-16T14:18:10+08:00","type":"BIZ","func":"go-brick/bpanic_test.TestRecover.func1","msg":"test recover","trace_id":"","err":{"code":500,"reason":"recover","detail":".(type)=string","next":"xxx"},"stack":[{"func":"go-brick/bpanic_test.TestRecover.func2","file":"D:/GitHub/go-brick/bpanic/recover_test.go:15"},{"func":"go-brick/bpanic_test.TestRecover","file":"D:/GitHub/go-brick/bpanic/recover_test.go:17"},{"func":"testing.tRunner","file":"D:/Programs/go1.19.1/go/src/testing/testing.go:1446"}]}
 }
+
+func TestRecoverPanicType(t *testing.T) {
+	recoverLabel := func(f func()) (label string) {
+		defer bpanic.Recover(func(err error) {
+			label = berror.Labels(err)["panic_type"]
+		})
+		f()
+		return
+	}
+
+	assert.Equal(t, bpanic.PanicTypeNilPointer, recoverLabel(func() {
+		var p *struct{ v int }
+		_ = p.v
+	}))
+	assert.Equal(t, bpanic.PanicTypeIndexOutOfRange, recoverLabel(func() {
+		s := []int{1}
+		i := 3
+		_ = s[i]
+	}))
+	assert.Equal(t, bpanic.PanicTypeTypeAssertion, recoverLabel(func() {
+		var v any = "x"
+		_ = v.(int)
+	}))
+	assert.Equal(t, bpanic.PanicTypeBusiness, recoverLabel(func() {
+		panic("xxx")
+	}))
+}
@@ -129,6 +129,37 @@ func TakeStack(skip int, depth StacktraceDepth) StackList {
 	return stackFmt.Stack()
 }
 
+// TakeStackFiltered captures the call stack like TakeStack,
+// but omits any frame whose function name starts with one of @skipPrefixes,
+// e.g. "runtime." or "github.com/gin-gonic".
+// the result is ordered innermost-first and holds at most @max frames after filtering,
+// a non-positive @max means no limit.
+func TakeStackFiltered(skip, max int, skipPrefixes []string) StackList {
+	stack := captureStacktrace(skip+1, StacktraceFull)
+	defer stack.Free()
+
+	stackFmt := newStackFormatter(stack.Count())
+	for frame, more := stack.Next(); more; frame, more = stack.Next() {
+		if max > 0 && len(stackFmt.list) >= max {
+			break
+		}
+		if hasAnyPrefix(frame.Function, skipPrefixes) {
+			continue
+		}
+		stackFmt.FormatFrame(frame)
+	}
+	return stackFmt.Stack()
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // stackFormatter formats a stack trace into a readable string representation.
 type stackFormatter struct {
 	list StackList
@@ -223,6 +254,46 @@ func (sl StackList) MarshalLogArray(enc zapcore.ArrayEncoder) (err error) {
 	return
 }
 
+// Frame the parsed information of a captured stack frame
+type Frame struct {
+	File     string // file name
+	Line     int    // line no
+	Function string // function name
+}
+
+// Frames returns the parsed frames that have been captured, innermost-first.
+// returns an empty slice if the list is empty.
+func (sl StackList) Frames() []Frame {
+	frames := make([]Frame, 0, len(sl))
+	for _, s := range sl {
+		if s == nil {
+			continue
+		}
+		frames = append(frames, Frame{
+			File:     s.File,
+			Line:     s.Line,
+			Function: s.Func,
+		})
+	}
+	return frames
+}
+
+// Origin returns a "dir/file.go:line (Func)" description of the innermost frame,
+// preserving only the leaf directory name, file name and short function name.
+// returns an empty string if the list is empty.
+func (sl StackList) Origin() string {
+	if len(sl) == 0 || sl[0] == nil {
+		return ""
+	}
+	s := sl[0]
+	var sf stackFormatter
+	fn := s.Func
+	if idx := strings.LastIndexByte(fn, '.'); idx != -1 {
+		fn = fn[idx+1:]
+	}
+	return sf.TrimmedPath(s.File) + ":" + strconv.Itoa(s.Line) + " (" + fn + ")"
+}
+
 func (sl StackList) Error() string {
 	out, _ := json.MarshalToString(sl)
 	return out
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/lamber92/go-brick/bstack"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestTakeStack(t *testing.T) {
@@ -11,3 +12,34 @@ func TestTakeStack(t *testing.T) {
 	t.Logf("%s", stack)
 	// [{"func":"go-brick/bstack_test.TestTakeStack","file":"D:/GitHub/go-brick/bstack/stacktrace_test.go","line":9},{"func":"testing.tRunner","file":"D:/Programs/go1.19.1/go/src/testing/testing.go","line":1446}]
 }
+
+func TestStackList_Origin(t *testing.T) {
+	stack := bstack.TakeStack(0, bstack.StacktraceMax)
+	assert.Regexp(t, `^bstack/stacktrace_test\.go:\d+ \(TestStackList_Origin\)$`, stack.Origin())
+
+	assert.Equal(t, "", bstack.StackList{}.Origin())
+}
+
+func TestTakeStackFiltered(t *testing.T) {
+	full := bstack.TakeStack(0, bstack.StacktraceFull)
+	assert.Contains(t, full.Error(), `"testing.tRunner"`)
+
+	stack := bstack.TakeStackFiltered(0, 10, []string{"testing."})
+	assert.NotEmpty(t, stack)
+	assert.NotContains(t, stack.Error(), `"testing.`)
+	assert.Regexp(t, `\(TestTakeStackFiltered\)$`, stack.Origin())
+
+	// respect the max cap after filtering
+	assert.Len(t, bstack.TakeStackFiltered(0, 1, nil), 1)
+}
+
+func TestStackList_Frames(t *testing.T) {
+	stack := bstack.TakeStack(0, bstack.StacktraceMax)
+	frames := stack.Frames()
+	assert.Len(t, frames, len(stack))
+	assert.Equal(t, "github.com/lamber92/go-brick/bstack_test.TestStackList_Frames", frames[0].Function)
+	assert.Regexp(t, `bstack/stacktrace_test\.go$`, frames[0].File)
+	assert.Greater(t, frames[0].Line, 0)
+
+	assert.Equal(t, []bstack.Frame{}, bstack.StackList{}.Frames())
+}
@@ -1,6 +1,6 @@
 module github.com/lamber92/go-brick
 
-go 1.19
+go 1.20
 
 replace github.com/apolloconfig/agollo/v4 => github.com/lamber92/agollo/v4 v4.3.2
 
@@ -15,6 +15,8 @@ require (
 	github.com/spf13/cast v1.5.1
 	github.com/spf13/viper v1.15.0
 	github.com/stretchr/testify v1.8.1
+	go.opentelemetry.io/otel v1.13.0
+	go.opentelemetry.io/otel/trace v1.13.0
 	go.uber.org/zap v1.24.0
 	google.golang.org/grpc v1.52.3
 	google.golang.org/protobuf v1.28.1